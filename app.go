@@ -3,12 +3,17 @@ package byke
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 // App provides an entry point to your application.
 type App struct {
 	world *World
 	run   Runner
+
+	plugins             []NamedPlugin
+	pluginsFinished     bool
+	pluginsReadyTimeout time.Duration
 }
 
 // World returns the world created by the App.
@@ -22,7 +27,9 @@ func (a *App) World() *World {
 	return a.world
 }
 
-// AddPlugin adds the given Plugin to this app.
+// AddPlugin adds the given Plugin to this app. Plugins added this way are
+// applied directly and are not checked for duplicates, use AddNamedPlugin
+// for plugins that must only be added once.
 func (a *App) AddPlugin(plugin Plugin) {
 	plugin(a)
 }
@@ -80,8 +87,30 @@ func (a *App) InitState[S comparable](initialValue S) {
 
 // AddMessage configures a new event in the World.
 // Use MessageType to acquire a value implementing AddMessageType.
-func (a *App) AddMessage[E any]() {
-	a.AddPlugin(pluginMessage[E])
+// Without a config, the messages use MessageUpdateFrame.
+func (a *App) AddMessage[E any](config ...MessageConfig) {
+	switch len(config) {
+	case 0:
+		a.AddPlugin(pluginMessage[E](MessageConfig{}))
+	case 1:
+		a.AddPlugin(pluginMessage[E](config[0]))
+	default:
+		panic("AddMessage must be invoked with zero or one parameter")
+	}
+}
+
+// AddConcurrentMessage configures a ConcurrentMessageSender for messages of type E.
+// The message type is registered using AddMessage if that has not happened yet.
+// Without a config, the sender uses BackpressureUnbounded.
+func (a *App) AddConcurrentMessage[E any](config ...ConcurrentMessageConfig) {
+	switch len(config) {
+	case 0:
+		a.AddPlugin(pluginConcurrentMessage[E](ConcurrentMessageConfig{}))
+	case 1:
+		a.AddPlugin(pluginConcurrentMessage[E](config[0]))
+	default:
+		panic("AddConcurrentMessage must be invoked with zero or one parameter")
+	}
 }
 
 // RunWorld configures the function that is executed in Run.
@@ -103,6 +132,10 @@ func (a *App) Run() error {
 		}
 	}
 
+	if err := a.FinishPlugins(); err != nil {
+		return err
+	}
+
 	a.World().PrintSystems()
 
 	return a.run(a.World())
@@ -117,6 +150,8 @@ func (a *App) MustRun() {
 
 // Plugin for an App.
 // Call App.AddPlugin to add a Plugin to an App.
+// See NamedPlugin for plugins that must only be added once
+// or that need to run code after all plugins were added.
 type Plugin func(app *App)
 
 type Runner func(world *World) error
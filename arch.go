@@ -43,6 +43,17 @@ type ImmutableComponent[T spoke.IsImmutableComponent[T]] = spoke.ImmutableCompon
 // struct into a comparable component (see IsComponent).
 type ComparableComponent[T IsComparableComponent[T]] = spoke.ComparableComponent[T]
 
+// SparseComponent is a zero sized type that may be embedded into a struct to turn that
+// struct into a component that is stored in a sparse set instead of an archetype.
+// Adding and removing sparse components is cheap, as the entity keeps its archetype.
+// Use it for components that are toggled often, e.g. a Selected or Hovered marker.
+type SparseComponent[T IsComponent[T]] = spoke.SparseComponent[T]
+
+// Disabled marks an entity as disabled. Queries do not match disabled entities,
+// unless they opt in using Allow[Disabled], With[Disabled] or by fetching Disabled.
+// Use EntityCommands.DisableHierarchy to disable an entity and all its descendants.
+type Disabled = spoke.Disabled
+
 // ErasedComponent indicates a type erased Component value.
 //
 // Values given to the consumer of byke of this type are usually pointers,
@@ -73,6 +84,10 @@ type With[C IsComponent[C]] = query.With[C]
 // entities that do not have a Component of type T.
 type Without[C IsComponent[C]] = query.Without[C]
 
+// Allow is a query filter that opts a query in to match entities that would be
+// excluded by default, e.g. Allow[Disabled] also matches disabled entities.
+type Allow[C IsComponent[C]] = query.Allow[C]
+
 // Added is a query filter that constraints the entities matched to include only
 // entities that have added a component of type C since the last tick that the
 // system owning the Query ran.
@@ -82,9 +97,23 @@ type Added[C IsComponent[C]] = query.Added[C]
 // entities that have a changed Component value of type C since the last tick that the
 // system owning the Query ran.
 //
-// Change detection currently works by hashing the component value. As such, matching
-// for changed component values is not 100% foolproof in case of hash collisions.
-type Changed[C spoke.IsSupportsChangeDetectionComponent[C]] = query.Changed[C]
+// For comparable components, change detection works by comparing the component values
+// after a system queried them by pointer. Other components are only marked as changed
+// when they are inserted again, or when they are accessed using Mut.
+type Changed[C IsComponent[C]] = query.Changed[C]
+
+// Mut is a query parameter that fetches a Component of type C for mutation.
+// Calling Mut.Get marks the component as changed. Use Mut.BypassChangeDetection
+// to modify the component without marking it changed.
+type Mut[C IsComponent[C]] = query.Mut[C]
+
+// Ticks is a query parameter that provides the ticks at which a Component of type C
+// was added and last changed. Use it to check for changes per item, instead of
+// filtering the whole query using Changed.
+type Ticks[C IsComponent[C]] = query.Ticks[C]
+
+// Tick counts system executions in a World.
+type Tick = spoke.Tick
 
 // Or is a query filter that allows you to combine two query filters with a local 'or'.
 // Simply adding multiple filters to a query requires all filters to match. Using Or
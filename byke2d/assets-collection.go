@@ -0,0 +1,117 @@
+package byke2d
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// AssetCollection groups assets, e.g. all assets of a level, to track their loading
+// progress as a group. The collection holds strong handles to its assets. Call Release
+// to allow the assets to be unloaded.
+type AssetCollection struct {
+	handles []collectedAsset
+}
+
+type collectedAsset struct {
+	Path   string
+	Handle Releaser
+}
+
+// Load loads an asset as part of the collection.
+func (c *AssetCollection) Load[T any](assets *Assets, path string) AsyncAsset[T] {
+	return c.LoadWithSettings[T](assets, path, nil)
+}
+
+// LoadWithSettings loads an asset as part of the collection.
+func (c *AssetCollection) LoadWithSettings[T any](assets *Assets, path string, settings LoadAssetSettings) AsyncAsset[T] {
+	handle := assets.LoadHandleWithSettings[T](path, settings)
+	c.handles = append(c.handles, collectedAsset{Path: handle.Path(), Handle: handle})
+	return handle
+}
+
+// Progress returns the loading progress of all assets in this collection,
+// including their dependencies.
+func (c *AssetCollection) Progress(assets *Assets) AssetCollectionProgress {
+	progress := AssetCollectionProgress{
+		Total: len(c.handles),
+	}
+
+	for _, asset := range c.handles {
+		loadState, err := assets.LoadStateWithDependencies(asset.Path)
+
+		switch loadState {
+		case LoadStateLoaded:
+			progress.Loaded += 1
+
+		case LoadStateFailed:
+			progress.Errors = append(progress.Errors, err)
+		}
+	}
+
+	return progress
+}
+
+// Release releases the handles to all assets in this collection.
+func (c *AssetCollection) Release() {
+	for _, asset := range c.handles {
+		asset.Handle.Release()
+	}
+
+	c.handles = nil
+}
+
+type AssetCollectionProgress struct {
+	// Number of assets that are loaded with all their dependencies
+	Loaded int
+
+	// Total number of assets in the collection
+	Total int
+
+	// Errors of all assets that failed to load
+	Errors []error
+}
+
+// IsReady returns true, if all assets were loaded successfully.
+func (p AssetCollectionProgress) IsReady() bool {
+	return p.Loaded == p.Total
+}
+
+// Fraction returns the progress as a value between 0 and 1.
+func (p AssetCollectionProgress) Fraction() float32 {
+	if p.Total == 0 {
+		return 1
+	}
+
+	return float32(p.Loaded) / float32(p.Total)
+}
+
+// Err returns the errors of all failed assets joined together.
+func (p AssetCollectionProgress) Err() error {
+	return errors.Join(p.Errors...)
+}
+
+// TransitionWhenLoaded returns a system that moves the State from loading to next, once
+// all assets of the collection are loaded with their dependencies. If assets
+// fail to load, the errors are logged and the state stays in loading.
+//
+//	app.AddSystems(byke.Update, byke2d.TransitionWhenLoaded(&levelAssets, GameStateLoading, GameStateInGame))
+func TransitionWhenLoaded[S comparable](collection *AssetCollection, loading, next S) byke.Systems {
+	system := func(assets *Assets, nextState *byke.NextState[S], reportedErrors *byke.Local[int]) {
+		progress := collection.Progress(assets)
+
+		// log newly failed assets
+		for _, err := range progress.Errors[min(reportedErrors.Value, len(progress.Errors)):] {
+			slog.Warn("Failed to load asset of collection", slog.String("err", err.Error()))
+		}
+
+		reportedErrors.Value = len(progress.Errors)
+
+		if progress.IsReady() {
+			nextState.Set(next)
+		}
+	}
+
+	return byke.System(system).RunIf(byke.InState(loading))
+}
@@ -0,0 +1,158 @@
+package byke2d
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingAssetLoader loads a testAsset once the unblock channel is closed.
+type blockingAssetLoader struct {
+	unblock chan struct{}
+}
+
+func (l blockingAssetLoader) Load(ctx LoadContext, r io.ReadSeekCloser) (any, error) {
+	defer func() { _ = r.Close() }()
+
+	<-l.unblock
+	return &testAsset{}, nil
+}
+
+func (blockingAssetLoader) Type() reflect.Type {
+	return reflect.TypeFor[*testAsset]()
+}
+
+func (blockingAssetLoader) Extensions() []string {
+	return []string{".test"}
+}
+
+// parentAssetLoader loads a testAsset depending on the file named in its content.
+type parentAssetLoader struct{}
+
+func (parentAssetLoader) Load(ctx LoadContext, r io.ReadSeekCloser) (any, error) {
+	defer func() { _ = r.Close() }()
+
+	dependency, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.LoadDependency[*testAsset](string(dependency))
+
+	return &testAsset{}, nil
+}
+
+func (parentAssetLoader) Type() reflect.Type {
+	return reflect.TypeFor[*testAsset]()
+}
+
+func (parentAssetLoader) Extensions() []string {
+	return []string{".parent"}
+}
+
+func TestAssetCollectionProgress(t *testing.T) {
+	loader := blockingAssetLoader{unblock: make(chan struct{})}
+
+	assets := makeAssets(nil, fstest.MapFS{"a.test": {}, "b.test": {}}, loader)
+
+	var collection AssetCollection
+	a := collection.Load[*testAsset](&assets, "a.test")
+	b := collection.Load[*testAsset](&assets, "b.test")
+
+	// nothing is loaded yet
+	progress := collection.Progress(&assets)
+	require.Equal(t, AssetCollectionProgress{Loaded: 0, Total: 2}, progress)
+	require.False(t, progress.IsReady())
+	require.Zero(t, progress.Fraction())
+
+	close(loader.unblock)
+	a.Await()
+	b.Await()
+
+	progress = collection.Progress(&assets)
+	require.Equal(t, AssetCollectionProgress{Loaded: 2, Total: 2}, progress)
+	require.True(t, progress.IsReady())
+	require.Equal(t, float32(1), progress.Fraction())
+	require.NoError(t, progress.Err())
+
+	// the assets are unloaded after the collection was released
+	collection.Release()
+	assets.states.update(assets.generic)
+
+	_, ok := assets.generic.Cached("a.test")
+	require.False(t, ok)
+}
+
+func TestAssetCollectionWithFailingDependency(t *testing.T) {
+	assets := makeAssets(nil, fstest.MapFS{
+		"level.parent": {Data: []byte("missing.test")},
+		"player.test":  {},
+	}, parentAssetLoader{}, blockingAssetLoader{unblock: closedChannel()})
+
+	var collection AssetCollection
+	collection.Load[*testAsset](&assets, "level.parent").Await()
+	collection.Load[*testAsset](&assets, "player.test").Await()
+
+	// wait for the dependency to fail
+	require.Eventually(t, func() bool {
+		return len(collection.Progress(&assets).Errors) > 0
+	}, time.Second, time.Millisecond)
+
+	progress := collection.Progress(&assets)
+	require.Equal(t, 1, progress.Loaded)
+	require.Equal(t, 2, progress.Total)
+	require.False(t, progress.IsReady())
+	require.ErrorContains(t, progress.Err(), "missing.test")
+
+	require.Equal(t, []string{"missing.test"}, assets.Dependencies("level.parent"))
+}
+
+type testGameState int
+
+const (
+	testGameStateLoading testGameState = iota
+	testGameStateInGame
+)
+
+func TestTransitionWhenLoaded(t *testing.T) {
+	loader := blockingAssetLoader{unblock: make(chan struct{})}
+
+	var app byke.App
+	app.InsertResource(makeAssets(app.World(), fstest.MapFS{"a.test": {}}, loader))
+	app.InitState(testGameStateLoading)
+
+	var collection AssetCollection
+	asset := collection.Load[*testAsset](app.World().RequireResourceOf[Assets](), "a.test")
+
+	app.AddSystems(byke.Update, TransitionWhenLoaded(&collection, testGameStateLoading, testGameStateInGame))
+
+	currentState := func() testGameState {
+		return app.World().RequireResourceOf[byke.State[testGameState]]().Current()
+	}
+
+	world := app.World()
+
+	// stays in loading while the asset is loading
+	world.RunSchedule(byke.Main)
+	world.RunSchedule(byke.Main)
+	require.Equal(t, testGameStateLoading, currentState())
+
+	close(loader.unblock)
+	asset.Await()
+
+	// the next state is applied by the StateTransition schedule of the following frame
+	world.RunSchedule(byke.Main)
+	world.RunSchedule(byke.Main)
+	require.Equal(t, testGameStateInGame, currentState())
+}
+
+func closedChannel() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
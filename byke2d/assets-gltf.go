@@ -33,26 +33,64 @@ func (i GLTFLoader) Load(ctx LoadContext, r io.ReadSeekCloser) (any, error) {
 	// nothing yet
 	_ = settings
 
+	var h *gltf.Handle
+	var err error
+
 	switch {
 	case strings.HasSuffix(strings.ToLower(ctx.Path), ".glb"):
-		h, err := gltf.GLB(r)
+		h, err = gltf.GLB(r)
 		if err != nil {
 			return nil, fmt.Errorf("load glb: %w", err)
 		}
 
-		return h, nil
-
 	case strings.HasSuffix(strings.ToLower(ctx.Path), ".gltf"):
-		h, err := gltf.GLTF(assets, r)
+		h, err = gltf.GLTF(assets, r)
 		if err != nil {
 			return nil, fmt.Errorf("load gltf: %w", err)
 		}
 
-		return h, nil
-
 	default:
 		panic("unreachable")
 	}
+
+	loadGltfImageDependencies(ctx, h)
+
+	return h, nil
+}
+
+// loadGltfImageDependencies starts loading all external images referenced by the
+// materials of the gltf file as dependencies, using the same settings as
+// they are used with when spawning the scene.
+func loadGltfImageDependencies(ctx LoadContext, h *gltf.Handle) {
+	if ctx.assets == nil {
+		// not loaded using Assets
+		return
+	}
+
+	loadTexture := func(info *gltf.TextureInfo, linearColors bool) {
+		if info == nil || int(info.Index) >= len(h.Textures) {
+			return
+		}
+
+		source := h.Textures[info.Index].Source
+		if int(source) >= len(h.Images) || h.Images[source].Uri == "" {
+			return
+		}
+
+		settings := &LoadTextureSettings{Linear: linearColors}
+		ctx.LoadDependencyWithSettings[*Texture](h.Images[source].Uri, settings)
+	}
+
+	for _, material := range h.Materials {
+		if mr := material.MetallicRoughness; mr != nil {
+			loadTexture(mr.BaseColorTexture, false)
+			loadTexture(mr.MetallicRoughnessTexture, true)
+		}
+
+		loadTexture(material.NormalTexture, true)
+		loadTexture(material.OcclusionTexture, false)
+		loadTexture(material.EmissiveTexture, false)
+	}
 }
 
 func (i GLTFLoader) Extensions() []string {
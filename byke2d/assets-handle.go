@@ -0,0 +1,461 @@
+package byke2d
+
+import (
+	"image"
+	"log/slog"
+	"path"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/oliverbestmann/byke/byke2d/gltf"
+)
+
+var _ = byke.ValidateComponent[AssetHandles]()
+
+type AssetEventKind uint8
+
+const (
+	// AssetEventAdded is sent once an asset finished loading.
+	AssetEventAdded AssetEventKind = iota
+
+	// AssetEventModified is sent after an asset was reloaded.
+	AssetEventModified
+
+	// AssetEventRemoved is sent after an asset was unloaded.
+	AssetEventRemoved
+
+	// AssetEventFailed is sent if loading or reloading an asset failed.
+	AssetEventFailed
+
+	// AssetEventLoadedWithDependencies is sent once an asset and all
+	// its dependencies finished loading.
+	AssetEventLoadedWithDependencies
+)
+
+// AssetEvent is a message describing a change in the lifecycle of an asset of type T.
+// The messages are registered for all asset types with a builtin loader. Use
+// RegisterAssetEvents to register them for other asset types.
+type AssetEvent[T any] struct {
+	Kind AssetEventKind
+	Path string
+
+	// Error is set for AssetEventFailed
+	Error error
+}
+
+// RegisterAssetEvents registers the AssetEvent messages for assets of type T.
+func RegisterAssetEvents[T any](app *byke.App) {
+	app.AddMessage[AssetEvent[T]]()
+}
+
+func pluginAssets(app *byke.App) {
+	RegisterAssetEvents[*Texture](app)
+	RegisterAssetEvents[image.Image](app)
+	RegisterAssetEvents[*AudioSource](app)
+	RegisterAssetEvents[*gltf.Handle](app)
+	RegisterAssetEvents[*byke.Prefab](app)
+
+	app.World().RegisterComponentHooks[AssetHandles]().
+		OnDiscard(func(world byke.DeferredWorld, entity byke.EntityRef, componentType *byke.ComponentType) {
+			entity.Get(componentType).(*AssetHandles).release()
+		})
+
+	app.AddSystems(byke.First, byke.System(updateAssetStatesSystem).Internal())
+}
+
+// Handle is a strong, reference counted handle to an asset. An asset is unloaded
+// after all strong handles to it were released. Unloading releases the resources of
+// the asset, if it implements Releaser.
+//
+// The value of an asset must not be used after its last strong handle was released.
+// Keep a handle as long as the value is used, e.g. by adding an AssetHandles
+// component next to a Sprite using a texture.
+type Handle[T any] struct {
+	AsyncAsset[T]
+
+	state    *assetState
+	released atomic.Bool
+}
+
+// LoadHandle loads an asset and returns a strong handle to it.
+func (a *Assets) LoadHandle[T any](path string) *Handle[T] {
+	return a.LoadHandleWithSettings[T](path, nil)
+}
+
+// LoadHandleWithSettings loads an asset and returns a strong handle to it.
+func (a *Assets) LoadHandleWithSettings[T any](path string, settings LoadAssetSettings) *Handle[T] {
+	return newHandle[T](a.acquire[T](path, settings))
+}
+
+func newHandle[T any](state *assetState) *Handle[T] {
+	return &Handle[T]{
+		AsyncAsset: &typedAsyncAsset[T]{Asset: state.asset},
+		state:      state,
+	}
+}
+
+// Path returns the path of the asset this handle points to.
+func (h *Handle[T]) Path() string {
+	return h.state.Path
+}
+
+// LoadStateWithDependencies returns the load state of the asset and all its dependencies.
+func (h *Handle[T]) LoadStateWithDependencies() (LoadState, error) {
+	return h.state.loadStateWithDependencies()
+}
+
+// Clone returns a new strong handle to the same asset.
+func (h *Handle[T]) Clone() *Handle[T] {
+	if h.released.Load() {
+		panic("clone of a released handle")
+	}
+
+	h.state.strong.Add(1)
+	return newHandle[T](h.state)
+}
+
+// Weak returns a weak handle to the asset. A weak handle does not keep the asset loaded.
+func (h *Handle[T]) Weak() WeakHandle[T] {
+	return WeakHandle[T]{state: h.state}
+}
+
+// Release releases this handle. Calling Release multiple times has no effect.
+func (h *Handle[T]) Release() {
+	if h.released.CompareAndSwap(false, true) {
+		h.state.strong.Add(-1)
+	}
+}
+
+// WeakHandle is a handle to an asset that does not keep the asset loaded.
+type WeakHandle[T any] struct {
+	state *assetState
+}
+
+// Path returns the path of the asset this handle points to.
+func (h WeakHandle[T]) Path() string {
+	return h.state.Path
+}
+
+// Upgrade returns a new strong handle to the asset, if the asset was not yet unloaded.
+func (h WeakHandle[T]) Upgrade() (*Handle[T], bool) {
+	if h.state == nil || !h.state.acquire() {
+		return nil, false
+	}
+
+	return newHandle[T](h.state), true
+}
+
+// UntypedHandle is implemented by all Handle types.
+type UntypedHandle interface {
+	Path() string
+	Release()
+}
+
+// AssetHandles keeps assets loaded as long as the entity holding this component exists.
+// The handles are released once the component is removed or replaced, or the entity is
+// despawned. The component takes ownership of the handles.
+//
+//	texture := assets.LoadHandle[*byke2d.Texture]("player.png")
+//	commands.Spawn(
+//		byke2d.Sprite{Texture: texture.Await()},
+//		byke2d.AssetHandles{Handles: []byke2d.UntypedHandle{texture}},
+//	)
+type AssetHandles struct {
+	byke.Component[AssetHandles]
+	Handles []UntypedHandle
+}
+
+func (a *AssetHandles) release() {
+	for _, handle := range a.Handles {
+		handle.Release()
+	}
+}
+
+type assetEventEmitter func(world *byke.World, kind AssetEventKind, path string, err error)
+
+type assetState struct {
+	Path  string
+	asset *asyncAsset[any]
+	emit  assetEventEmitter
+
+	// the assets this asset depends on
+	dependencies *assetDependencies
+
+	// number of strong handles, -1 after the asset was unloaded
+	strong atomic.Int32
+
+	// true once the result of the initial load was reported
+	reported bool
+
+	// true once the asset was reported as loaded with all its dependencies
+	reportedWithDependencies bool
+}
+
+// acquire increments the number of strong handles. Returns false,
+// if the asset was already unloaded.
+func (s *assetState) acquire() bool {
+	for {
+		strong := s.strong.Load()
+		if strong < 0 {
+			return false
+		}
+
+		if s.strong.CompareAndSwap(strong, strong+1) {
+			return true
+		}
+	}
+}
+
+type assetDependencies struct {
+	mu     sync.Mutex
+	states []*assetState
+}
+
+// add adds a dependency. Returns false, if it already is a dependency.
+func (d *assetDependencies) add(state *assetState) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if slices.Contains(d.states, state) {
+		return false
+	}
+
+	d.states = append(d.states, state)
+	return true
+}
+
+func (d *assetDependencies) list() []*assetState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.states)
+}
+
+type queuedAssetEvent struct {
+	State *assetState
+	Kind  AssetEventKind
+	Error error
+}
+
+func emitAssetEvent[T any](world *byke.World, kind AssetEventKind, path string, err error) {
+	messages, ok := world.ResourceOf[byke.Messages[AssetEvent[T]]]()
+	if !ok {
+		// nobody is interested in events for this asset type
+		return
+	}
+
+	messages.Send(AssetEvent[T]{Kind: kind, Path: path, Error: err})
+}
+
+// assetStates tracks the lifecycle of the assets in the generic cache.
+type assetStates struct {
+	// guards byPath and events, as assets might be loaded
+	// as dependencies from within other loaders
+	mu sync.Mutex
+
+	byPath map[string]*assetState
+
+	// events that are sent with the next update
+	events []queuedAssetEvent
+}
+
+// stateOf returns the lifecycle state for the given asset, creating it if necessary.
+func (s *assetStates) stateOf(p string, asset *asyncAsset[any], dependencies *assetDependencies, emit assetEventEmitter) *assetState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p = path.Clean(p)
+
+	if state, ok := s.byPath[p]; ok && state.asset == asset {
+		return state
+	}
+
+	state := &assetState{
+		Path:         p,
+		asset:        asset,
+		emit:         emit,
+		dependencies: dependencies,
+	}
+
+	ensureMapIsInitialized(&s.byPath)
+	s.byPath[p] = state
+
+	return state
+}
+
+func (s *assetStates) get(p string) (*assetState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.byPath[path.Clean(p)]
+	return state, ok
+}
+
+// queueEvent queues an event for the asset at the given path, if it is a known asset.
+func (s *assetStates) queueEvent(p string, kind AssetEventKind, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.byPath[path.Clean(p)]; ok {
+		s.events = append(s.events, queuedAssetEvent{State: state, Kind: kind, Error: err})
+	}
+}
+
+// update queues events for finished loads and unloads assets
+// that are not referenced by any strong handle.
+func (s *assetStates) update(cache *assetCache[any]) []queuedAssetEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for p, state := range s.byPath {
+		value, err, done := state.asset.Poll()
+		if !done {
+			continue
+		}
+
+		if !state.reported {
+			state.reported = true
+
+			if err != nil {
+				s.events = append(s.events, queuedAssetEvent{State: state, Kind: AssetEventFailed, Error: err})
+			} else {
+				s.events = append(s.events, queuedAssetEvent{State: state, Kind: AssetEventAdded})
+			}
+		}
+
+		if !state.reportedWithDependencies && err == nil {
+			if loadState, _ := state.loadStateWithDependencies(); loadState == LoadStateLoaded {
+				state.reportedWithDependencies = true
+				s.events = append(s.events, queuedAssetEvent{State: state, Kind: AssetEventLoadedWithDependencies})
+			}
+		}
+
+		if !state.strong.CompareAndSwap(0, -1) {
+			continue
+		}
+
+		// no strong handles are left, unload the asset
+		delete(s.byPath, p)
+		cache.Remove(p)
+
+		if releaser, ok := value.(Releaser); ok && err == nil {
+			releaser.Release()
+		}
+
+		// the dependencies are not needed by this asset anymore
+		for _, dependency := range state.dependencies.list() {
+			dependency.strong.Add(-1)
+		}
+
+		slog.Debug("Unloaded asset", slog.String("path", p))
+
+		s.events = append(s.events, queuedAssetEvent{State: state, Kind: AssetEventRemoved})
+	}
+
+	events := s.events
+	s.events = nil
+
+	return events
+}
+
+func updateAssetStatesSystem(world *byke.World) {
+	assets := world.RequireResourceOf[Assets]()
+
+	for _, event := range assets.states.update(assets.generic) {
+		event.State.emit(world, event.Kind, event.State.Path, event.Error)
+	}
+}
+
+type LoadState uint8
+
+const (
+	// LoadStateNotLoaded indicates that the asset was never loaded or was unloaded.
+	LoadStateNotLoaded LoadState = iota
+	LoadStateLoading
+	LoadStateLoaded
+	LoadStateFailed
+)
+
+// LoadStateWithDependencies returns the load state of the asset at the given path
+// including all its dependencies. The asset is only loaded, if all its dependencies
+// are loaded too. If the asset or any dependency failed to load, the error is returned.
+func (a *Assets) LoadStateWithDependencies(path string) (LoadState, error) {
+	if a.states == nil {
+		return LoadStateNotLoaded, nil
+	}
+
+	state, ok := a.states.get(path)
+	if !ok {
+		return LoadStateNotLoaded, nil
+	}
+
+	return state.loadStateWithDependencies()
+}
+
+// Dependencies returns the paths of the direct dependencies of the asset at the given path.
+func (a *Assets) Dependencies(path string) []string {
+	if a.states == nil {
+		return nil
+	}
+
+	state, ok := a.states.get(path)
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, dependency := range state.dependencies.list() {
+		paths = append(paths, dependency.Path)
+	}
+
+	return paths
+}
+
+func (s *assetState) loadState() (LoadState, error) {
+	_, err, done := s.asset.Poll()
+
+	switch {
+	case !done:
+		return LoadStateLoading, nil
+	case err != nil:
+		return LoadStateFailed, err
+	default:
+		return LoadStateLoaded, nil
+	}
+}
+
+func (s *assetState) loadStateWithDependencies() (LoadState, error) {
+	return s.collectLoadState(map[*assetState]bool{})
+}
+
+func (s *assetState) collectLoadState(visited map[*assetState]bool) (LoadState, error) {
+	if visited[s] {
+		// already checked, dependencies might contain cycles
+		return LoadStateLoaded, nil
+	}
+
+	visited[s] = true
+
+	loadState, err := s.loadState()
+	if loadState != LoadStateLoaded {
+		return loadState, err
+	}
+
+	// all dependencies are known once the asset itself is loaded
+	for _, dependency := range s.dependencies.list() {
+		depState, err := dependency.collectLoadState(visited)
+
+		switch depState {
+		case LoadStateFailed:
+			return LoadStateFailed, err
+
+		case LoadStateLoading:
+			// keep looking for failed dependencies
+			loadState = LoadStateLoading
+		}
+	}
+
+	return loadState, nil
+}
@@ -0,0 +1,253 @@
+package byke2d
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+type testAsset struct {
+	released int
+}
+
+func (a *testAsset) Release() {
+	a.released++
+}
+
+// loadTestAsset loads the value into the cache and returns the state tracking it.
+func loadTestAsset(t *testing.T, states *assetStates, cache *assetCache[any], path string, value any) *assetState {
+	t.Helper()
+
+	asset := cache.Get(path, func() (any, error) {
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+
+		return value, nil
+	})
+
+	// wait for the load to finish
+	_, _ = asset.TryAwait()
+
+	return states.stateOf(path, asset, &assetDependencies{}, nil)
+}
+
+func eventKindsOf(events []queuedAssetEvent, path string) []AssetEventKind {
+	var kinds []AssetEventKind
+	for _, event := range events {
+		if event.State.Path == path {
+			kinds = append(kinds, event.Kind)
+		}
+	}
+
+	return kinds
+}
+
+func TestAssetStatesUpdate(t *testing.T) {
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	value := &testAsset{}
+	state := loadTestAsset(t, &states, cache, "a.png", value)
+	state.strong.Add(1)
+
+	failed := loadTestAsset(t, &states, cache, "b.png", errors.New("broken"))
+	failed.strong.Add(1)
+
+	events := states.update(cache)
+	require.Equal(t, []AssetEventKind{AssetEventAdded, AssetEventLoadedWithDependencies}, eventKindsOf(events, "a.png"))
+	require.Equal(t, []AssetEventKind{AssetEventFailed}, eventKindsOf(events, "b.png"))
+
+	// events are only reported once
+	require.Empty(t, states.update(cache))
+
+	// the asset is unloaded after the last strong reference is gone
+	state.strong.Add(-1)
+
+	events = states.update(cache)
+	require.Equal(t, []AssetEventKind{AssetEventRemoved}, eventKindsOf(events, "a.png"))
+	require.Equal(t, 1, value.released)
+
+	_, ok := cache.Cached("a.png")
+	require.False(t, ok)
+
+	_, ok = states.get("a.png")
+	require.False(t, ok)
+
+	_, ok = states.get("b.png")
+	require.True(t, ok)
+}
+
+type testAssetLoader struct{}
+
+func (testAssetLoader) Load(ctx LoadContext, r io.ReadSeekCloser) (any, error) {
+	defer func() { _ = r.Close() }()
+	return &testAsset{}, nil
+}
+
+func (testAssetLoader) Type() reflect.Type {
+	return reflect.TypeFor[*testAsset]()
+}
+
+func (testAssetLoader) Extensions() []string {
+	return []string{".test"}
+}
+
+func TestLoadKeepsAssetLoaded(t *testing.T) {
+	assets := makeAssets(nil, fstest.MapFS{"a.test": {}}, testAssetLoader{})
+
+	value := assets.Load[*testAsset]("a.test").Await()
+
+	// a handle to the same asset is released again
+	assets.LoadHandle[*testAsset]("a.test").Release()
+
+	assets.states.update(assets.generic)
+	assets.states.update(assets.generic)
+
+	require.Zero(t, value.released)
+
+	_, ok := assets.generic.Cached("a.test")
+	require.True(t, ok)
+}
+
+func TestHandleRelease(t *testing.T) {
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	value := &testAsset{}
+	state := loadTestAsset(t, &states, cache, "a.png", value)
+	state.strong.Add(1)
+
+	handle := newHandle[*testAsset](state)
+	clone := handle.Clone()
+	require.Equal(t, int32(2), state.strong.Load())
+
+	// releasing a handle twice has no effect
+	handle.Release()
+	handle.Release()
+	require.Equal(t, int32(1), state.strong.Load())
+	require.Panics(t, func() { handle.Clone() })
+
+	states.update(cache)
+	require.Zero(t, value.released)
+
+	clone.Release()
+	events := states.update(cache)
+
+	require.Contains(t, eventKindsOf(events, "a.png"), AssetEventRemoved)
+	require.Equal(t, 1, value.released)
+}
+
+func TestWeakHandleUpgrade(t *testing.T) {
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	state := loadTestAsset(t, &states, cache, "a.png", &testAsset{})
+	state.strong.Add(1)
+
+	handle := newHandle[*testAsset](state)
+	weak := handle.Weak()
+	require.Equal(t, "a.png", weak.Path())
+
+	// a weak handle can be upgraded while the asset is loaded
+	upgraded, ok := weak.Upgrade()
+	require.True(t, ok)
+	require.Equal(t, int32(2), state.strong.Load())
+
+	handle.Release()
+	upgraded.Release()
+	states.update(cache)
+
+	// but not after the asset was unloaded
+	_, ok = weak.Upgrade()
+	require.False(t, ok)
+
+	_, ok = WeakHandle[*testAsset]{}.Upgrade()
+	require.False(t, ok)
+}
+
+func TestHandleValueDoesNotKeepAssetLoaded(t *testing.T) {
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	value := &testAsset{}
+	state := loadTestAsset(t, &states, cache, "a.png", value)
+	state.strong.Add(1)
+
+	handle := newHandle[*testAsset](state)
+	require.Same(t, value, handle.Await())
+
+	// accessing the value does not keep the asset loaded
+	handle.Release()
+	states.update(cache)
+
+	require.Equal(t, 1, value.released)
+
+	_, ok := cache.Cached("a.png")
+	require.False(t, ok)
+}
+
+func TestAssetHandlesReleasedOnDespawn(t *testing.T) {
+	var app byke.App
+	app.AddPlugin(pluginAssets)
+
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	state := loadTestAsset(t, &states, cache, "a.png", &testAsset{})
+	state.strong.Add(1)
+
+	world := app.World()
+	entityId := world.Spawn([]byke.ErasedComponent{
+		AssetHandles{Handles: []UntypedHandle{newHandle[*testAsset](state)}},
+	})
+
+	require.Equal(t, int32(1), state.strong.Load())
+
+	world.Despawn(entityId)
+	require.Zero(t, state.strong.Load())
+}
+
+func TestAssetDependencyRefcount(t *testing.T) {
+	var states assetStates
+	cache := &assetCache[any]{}
+
+	parentValue := &testAsset{}
+	parent := loadTestAsset(t, &states, cache, "scene.gltf", parentValue)
+	parent.strong.Add(1)
+
+	dependencyValue := &testAsset{}
+	dependency := loadTestAsset(t, &states, cache, "texture.png", dependencyValue)
+
+	// the parent and a handle reference the dependency
+	require.True(t, dependency.acquire())
+	require.True(t, parent.dependencies.add(dependency))
+	require.False(t, parent.dependencies.add(dependency))
+
+	handle := newHandle[*testAsset](dependency)
+	require.True(t, dependency.acquire())
+	require.Equal(t, int32(2), dependency.strong.Load())
+
+	loadState, err := parent.loadStateWithDependencies()
+	require.NoError(t, err)
+	require.Equal(t, LoadStateLoaded, loadState)
+
+	// unloading the parent releases its reference to the dependency
+	parent.strong.Add(-1)
+	states.update(cache)
+
+	require.Equal(t, 1, parentValue.released)
+	require.Equal(t, int32(1), dependency.strong.Load())
+	require.Zero(t, dependencyValue.released)
+
+	// the dependency is unloaded once the handle is released too
+	handle.Release()
+	states.update(cache)
+
+	require.Equal(t, 1, dependencyValue.released)
+}
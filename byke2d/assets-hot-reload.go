@@ -0,0 +1,214 @@
+package byke2d
+
+import (
+	"io/fs"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/oliverbestmann/byke/byke2d/gltf"
+)
+
+// AssetModified is a message sent after an asset was reloaded
+// by PluginAssetHotReload.
+type AssetModified struct {
+	// Path of the asset within the AssetFS
+	Path string
+}
+
+type AssetHotReloadConfig struct {
+	// Interval in which the modification times of the asset files are checked.
+	// Defaults to 500ms.
+	Interval time.Duration
+}
+
+// PluginAssetHotReload watches the files of all loaded assets and the shader files
+// added using Shaders.AddAsset for modifications. Modified assets are reloaded and an
+// AssetModified message is sent. Assets implementing Reloadable, like *Texture, are replaced
+// in place, so existing users see the new version. Other assets are replaced in the asset
+// cache only. Spawned glTF scenes are spawned again. If reloading fails, the previous
+// version is kept.
+//
+// Watching uses the modification times of the files and is only supported
+// for native builds.
+func PluginAssetHotReload(config AssetHotReloadConfig) byke.Plugin {
+	if config.Interval <= 0 {
+		config.Interval = 500 * time.Millisecond
+	}
+
+	return func(app *byke.App) {
+		app.AddMessage[AssetModified]()
+
+		if runtime.GOOS == "js" {
+			slog.Warn("Asset hot reloading is not supported in the browser")
+			return
+		}
+
+		app.InsertResource(assetWatcher{
+			modTimes: map[string]time.Time{},
+		})
+
+		app.AddSystems(byke.First, byke.System(watchModifiedAssetsSystem).
+			RunIf(byke.TimerJustFinished(byke.NewTimer(config.Interval, byke.TimerModeRepeating))))
+
+		app.AddSystems(byke.First, byke.System(finishAssetReloadsSystem).After(watchModifiedAssetsSystem))
+
+		app.AddSystems(byke.PostUpdate, byke.System(respawnReloadedScenesSystem).Before(spawnGltfSceneSystem))
+	}
+}
+
+type assetWatcher struct {
+	// last seen modification time by path
+	modTimes map[string]time.Time
+	pending  []pendingAssetReload
+}
+
+type pendingAssetReload struct {
+	Path string
+	Poll func() (bool, error)
+}
+
+// assetReloader is implemented by the asset caches
+type assetReloader interface {
+	Reload(path string) (func() (bool, error), bool)
+}
+
+// modified returns the paths of all files that were modified since they were last checked.
+// Files seen for the first time are not reported as modified.
+func (w *assetWatcher) modified(fsys fs.FS, paths []string) []string {
+	var modified []string
+
+	for _, path := range paths {
+		stat, err := fs.Stat(fsys, path)
+		if err != nil {
+			continue
+		}
+
+		previous, seen := w.modTimes[path]
+		w.modTimes[path] = stat.ModTime()
+
+		if seen && stat.ModTime().After(previous) {
+			modified = append(modified, path)
+		}
+	}
+
+	return modified
+}
+
+func watchModifiedAssetsSystem(
+	watcher *assetWatcher,
+	assets *Assets,
+	shaders *Shaders,
+	writer *byke.MessageWriter[AssetModified],
+) {
+	var paths []string
+	paths = append(paths, assets.generic.Paths()...)
+	paths = append(paths, assets.bytes.Paths()...)
+	paths = append(paths, slices.Collect(maps.Values(shaders.assetPaths))...)
+
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	for _, path := range watcher.modified(assets.fs, paths) {
+		slog.Info("Asset was modified, reloading", slog.String("path", path))
+
+		for _, cache := range []assetReloader{assets.generic, assets.bytes} {
+			if poll, ok := cache.Reload(path); ok {
+				watcher.pending = append(watcher.pending, pendingAssetReload{Path: path, Poll: poll})
+			}
+		}
+
+		for name, shaderPath := range shaders.assetPaths {
+			if shaderPath != path {
+				continue
+			}
+
+			if err := shaders.AddAsset(assets, name, path); err != nil {
+				slog.Warn("Failed to reload shader, keeping previous version",
+					slog.String("path", path),
+					slog.String("err", err.Error()))
+
+				continue
+			}
+
+			writer.Write(AssetModified{Path: path})
+		}
+	}
+}
+
+func finishAssetReloadsSystem(
+	watcher *assetWatcher,
+	assets *Assets,
+	writer *byke.MessageWriter[AssetModified],
+) {
+	watcher.pending = slices.DeleteFunc(watcher.pending, func(reload pendingAssetReload) bool {
+		done, err := reload.Poll()
+		if !done {
+			return false
+		}
+
+		if err != nil {
+			slog.Warn("Failed to reload asset, keeping previous version",
+				slog.String("path", reload.Path),
+				slog.String("err", err.Error()))
+
+			assets.states.queueEvent(reload.Path, AssetEventFailed, err)
+			return true
+		}
+
+		slog.Info("Reloaded asset", slog.String("path", reload.Path))
+		writer.Write(AssetModified{Path: reload.Path})
+		assets.states.queueEvent(reload.Path, AssetEventModified, nil)
+
+		return true
+	})
+}
+
+// respawnReloadedScenesSystem spawns the scenes of reloaded glTF files again.
+func respawnReloadedScenesSystem(
+	commands *byke.Commands,
+	assets *Assets,
+	reader *byke.MessageReader[AssetModified],
+	scenesQuery byke.Query[struct {
+		EntityId  byke.EntityId
+		SceneRoot SceneRoot
+	}],
+	instancesQuery byke.Query[struct {
+		_        byke.With[SceneInstance]
+		EntityId byke.EntityId
+		ChildOf  byke.ChildOf
+	}],
+) {
+	for _, modified := range reader.Read() {
+		cached, ok := assets.generic.Cached(modified.Path)
+		if !ok {
+			continue
+		}
+
+		handle, ok := cached.(*gltf.Handle)
+		if !ok {
+			continue
+		}
+
+		for scene := range scenesQuery.Items() {
+			if scene.SceneRoot.Handle != handle {
+				continue
+			}
+
+			// despawn the previously spawned scene
+			for instance := range instancesQuery.Items() {
+				if instance.ChildOf.Parent == scene.EntityId {
+					commands.Entity(instance.EntityId).Despawn()
+				}
+			}
+
+			// insert the SceneRoot again to spawn the scene
+			commands.Entity(scene.EntityId).
+				Remove[SceneRoot]().
+				Insert(scene.SceneRoot)
+		}
+	}
+}
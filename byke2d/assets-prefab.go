@@ -0,0 +1,66 @@
+package byke2d
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// PrefabLoader loads a byke.Prefab from a file with the extension ".prefab.json".
+// See byke.DecodePrefab for the format of the file.
+//
+// Loaded prefabs are registered in byke.Prefabs using their path as name. If
+// PluginAssetHotReload is enabled, a modified prefab file is registered again,
+// which updates all spawned instances of the prefab:
+//
+//	assets.Load[*byke.Prefab]("enemies/goblin.prefab.json")
+//	commands.SpawnPrefab(byke.PrefabHandle("enemies/goblin.prefab.json"))
+type PrefabLoader struct{}
+
+func (p PrefabLoader) Type() reflect.Type {
+	return reflect.TypeFor[*byke.Prefab]()
+}
+
+func (p PrefabLoader) Load(ctx LoadContext, r io.ReadSeekCloser) (any, error) {
+	defer func() { _ = r.Close() }()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read prefab: %w", err)
+	}
+
+	return byke.DecodePrefab(data)
+}
+
+func (p PrefabLoader) Extensions() []string {
+	return []string{".prefab.json"}
+}
+
+func pluginPrefabAssets(app *byke.App) {
+	app.AddSystems(byke.First, byke.System(registerLoadedPrefabsSystem).After(updateAssetStatesSystem))
+}
+
+// registerLoadedPrefabsSystem registers prefabs in byke.Prefabs once they were loaded
+// or reloaded.
+func registerLoadedPrefabsSystem(
+	assets *Assets,
+	prefabs *byke.Prefabs,
+	reader *byke.MessageReader[AssetEvent[*byke.Prefab]],
+) {
+	for _, event := range reader.Read() {
+		if event.Kind != AssetEventAdded && event.Kind != AssetEventModified {
+			continue
+		}
+
+		value, ok := assets.generic.Cached(event.Path)
+		if !ok {
+			continue
+		}
+
+		if prefab, ok := value.(*byke.Prefab); ok {
+			prefabs.Register(event.Path, prefab)
+		}
+	}
+}
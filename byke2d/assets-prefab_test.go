@@ -0,0 +1,69 @@
+package byke2d
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoaderForMultiPartExtension(t *testing.T) {
+	assets := makeAssets(nil, fstest.MapFS{}, PrefabLoader{})
+
+	loader, ok := assets.loaderFor("enemies/Goblin.Prefab.json", reflect.TypeFor[*byke.Prefab]())
+	require.True(t, ok)
+	require.Equal(t, PrefabLoader{}, loader)
+
+	_, ok = assets.loaderFor("config.json", reflect.TypeFor[*byke.Prefab]())
+	require.False(t, ok)
+}
+
+func TestLoadedPrefabsAreRegistered(t *testing.T) {
+	const path = "goblin.prefab.json"
+
+	fs := fstest.MapFS{
+		path: {Data: []byte(`{"components": {"byke.Name": {"Name": "Goblin"}}}`)},
+	}
+
+	var app byke.App
+	app.AddPlugin(pluginAssets)
+	app.AddPlugin(pluginPrefabAssets)
+
+	world := app.World()
+	app.InsertResource(makeAssets(world, fs, PrefabLoader{}))
+
+	assets := world.RequireResourceOf[Assets]()
+	handle := assets.LoadHandle[*byke.Prefab](path)
+	handle.Await()
+
+	world.RunSchedule(byke.First)
+
+	nameOf := func() string {
+		prefab, ok := world.RequireResourceOf[byke.Prefabs]().Get(path)
+		require.True(t, ok)
+		return prefab.Components[0].(*byke.Name).Name
+	}
+
+	require.Equal(t, "Goblin", nameOf())
+
+	// reload the modified file like PluginAssetHotReload does
+	fs[path] = &fstest.MapFile{Data: []byte(`{"components": {"byke.Name": {"Name": "Orc"}}}`)}
+
+	poll, ok := assets.generic.Reload(path)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		done, err := poll()
+		require.NoError(t, err)
+		return done
+	}, time.Second, time.Millisecond)
+
+	assets.states.queueEvent(path, AssetEventModified, nil)
+
+	// the prefab is registered again
+	world.RunSchedule(byke.First)
+	require.Equal(t, "Orc", nameOf())
+}
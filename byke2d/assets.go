@@ -7,11 +7,14 @@ import (
 	"io"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"net/url"
 	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -45,6 +48,28 @@ type LoadContext struct {
 
 	// A load specific settings object
 	Settings LoadAssetSettings
+
+	assets       *Assets
+	dependencies *assetDependencies
+}
+
+// LoadDependency loads an asset the asset currently being loaded depends on.
+// The dependency is kept loaded as long as the dependent asset is loaded.
+// See Assets.LoadStateWithDependencies.
+func (ctx LoadContext) LoadDependency[T any](path string) AsyncAsset[T] {
+	return ctx.LoadDependencyWithSettings[T](path, nil)
+}
+
+// LoadDependencyWithSettings is like LoadDependency, but accepts load settings.
+func (ctx LoadContext) LoadDependencyWithSettings[T any](path string, settings LoadAssetSettings) AsyncAsset[T] {
+	state := ctx.assets.acquire[T](path, settings)
+
+	if !ctx.dependencies.add(state) {
+		// already a dependency, e.g. after a reload
+		state.strong.Add(-1)
+	}
+
+	return &typedAsyncAsset[T]{Asset: state.asset}
 }
 
 type AssetLoader interface {
@@ -61,6 +86,9 @@ type Assets struct {
 	generic *assetCache[any]
 
 	bytes *assetCache[[]byte]
+
+	// lifecycle of the assets in the generic cache
+	states *assetStates
 }
 
 type loaderKey struct {
@@ -75,6 +103,7 @@ func makeAssets(world *byke.World, fs fs.FS, loaders ...AssetLoader) Assets {
 		loaders: make(map[loaderKey]AssetLoader, 32),
 		generic: &assetCache[any]{},
 		bytes:   &assetCache[[]byte]{},
+		states:  &assetStates{},
 	}
 
 	for _, l := range loaders {
@@ -99,24 +128,46 @@ func (a *Assets) Load[T any](path string) AsyncAsset[T] {
 	return a.LoadWithSettings[T](path, nil)
 }
 
+// LoadWithSettings loads an asset. Assets loaded this way are never unloaded,
+// as the strong reference taken by the load is never released.
+// Use LoadHandleWithSettings to get a reference counted Handle instead.
 func (a *Assets) LoadWithSettings[T any](path string, settings LoadAssetSettings) AsyncAsset[T] {
+	state := a.acquire[T](path, settings)
+	return &typedAsyncAsset[T]{Asset: state.asset}
+}
+
+// acquire loads an asset and takes a strong reference to it.
+func (a *Assets) acquire[T any](path string, settings LoadAssetSettings) *assetState {
+	for {
+		state := a.load[T](path, settings)
+
+		// the state might have been unloaded just now, try again in that case
+		if state.acquire() {
+			return state
+		}
+	}
+}
+
+func (a *Assets) load[T any](path string, settings LoadAssetSettings) *assetState {
 	if a.generic == nil {
 		a.generic = &assetCache[any]{}
 	}
 
-	key := loaderKey{
-		Ext:  strings.ToLower(filepath.Ext(path)),
-		Type: reflect.TypeFor[T](),
+	if a.states == nil {
+		a.states = &assetStates{}
 	}
 
-	loader, ok := a.loaders[key]
+	loader, ok := a.loaderFor(path, reflect.TypeFor[T]())
 	if !ok {
-		err := fmt.Errorf("no loader for extension %q and type %q", key.Ext, key.Type.String())
+		err := fmt.Errorf("no loader for extension %q and type %q", filepath.Ext(path), reflect.TypeFor[T]().String())
 		panic(err)
 	}
 
 	path, _ = url.QueryUnescape(path)
 
+	// dependencies recorded by the loader
+	dependencies := &assetDependencies{}
+
 	asyncAsset := a.generic.Get(path, func() (any, error) {
 		fp, err := a.fs.Open(path)
 		if err != nil {
@@ -127,6 +178,9 @@ func (a *Assets) LoadWithSettings[T any](path string, settings LoadAssetSettings
 			Path:     path,
 			Settings: settings,
 			World:    a.world,
+
+			assets:       a,
+			dependencies: dependencies,
 		}
 
 		asset, err := loader.Load(ctx, readSeekerOf(fp))
@@ -137,7 +191,28 @@ func (a *Assets) LoadWithSettings[T any](path string, settings LoadAssetSettings
 		return asset, nil
 	})
 
-	return &typedAsyncAsset[T]{Asset: asyncAsset}
+	return a.states.stateOf(path, asyncAsset, dependencies, emitAssetEvent[T])
+}
+
+// loaderFor returns the loader for the given path and type. An extension might consist
+// of multiple parts, e.g. ".prefab.json", in which case the longest registered extension wins.
+func (a *Assets) loaderFor(path string, ty reflect.Type) (AssetLoader, bool) {
+	ext := strings.ToLower(filepath.Base(path))
+
+	for {
+		idx := strings.IndexByte(ext, '.')
+		if idx < 0 {
+			return nil, false
+		}
+
+		ext = ext[idx:]
+
+		if loader, ok := a.loaders[loaderKey{Ext: ext, Type: ty}]; ok {
+			return loader, true
+		}
+
+		ext = ext[1:]
+	}
 }
 
 func (a *Assets) Bytes(path string) AsyncAsset[[]byte] {
@@ -320,7 +395,12 @@ func (t *typedAsyncAsset[T]) Poll() (T, error, bool) {
 }
 
 type assetCache[T any] struct {
+	// guards values and loaders, as assets might be loaded
+	// as dependencies from within other loaders
+	mu sync.Mutex
+
 	values   map[string]*asyncAsset[T]
+	loaders  map[string]func() (T, error)
 	loading  atomic.Int32
 	finished atomic.Int32
 }
@@ -342,8 +422,12 @@ func (a *assetCache[T]) Finished() int32 {
 }
 
 func (a *assetCache[T]) Get(p string, load func() (T, error)) *asyncAsset[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	if a.values == nil {
 		a.values = make(map[string]*asyncAsset[T], 64)
+		a.loaders = make(map[string]func() (T, error), 64)
 	}
 
 	// cleanup path to improve cache hits
@@ -386,9 +470,108 @@ func (a *assetCache[T]) Get(p string, load func() (T, error)) *asyncAsset[T] {
 	// and put the promise into the cache
 	a.values[p] = asyncAsset
 
+	// remember how to load the asset, in case we need to reload it
+	a.loaders[p] = load
+
 	return asyncAsset
 }
 
+// Remove removes the asset at the given path from the cache.
+func (a *assetCache[T]) Remove(p string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p = path.Clean(p)
+	delete(a.values, p)
+	delete(a.loaders, p)
+}
+
+// Paths returns the paths of all assets in the cache.
+func (a *assetCache[T]) Paths() []string {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return slices.Collect(maps.Keys(a.values))
+}
+
+// Cached returns the current value of the asset at the given path,
+// if it finished loading successfully.
+func (a *assetCache[T]) Cached(p string) (T, bool) {
+	a.mu.Lock()
+	asset, ok := a.values[path.Clean(p)]
+	a.mu.Unlock()
+
+	if !ok {
+		var tZero T
+		return tZero, false
+	}
+
+	value, err, ok := asset.Poll()
+	return value, ok && err == nil
+}
+
+// Reload starts loading the asset at the given path again. The returned function polls
+// the reload and returns true once it is finished. A successfully reloaded value replaces
+// the previous value in place, if the asset implements Reloadable. Otherwise, the
+// cached value is replaced. If the reload fails, the previous value is kept.
+func (a *assetCache[T]) Reload(p string) (poll func() (bool, error), ok bool) {
+	p = path.Clean(p)
+
+	a.mu.Lock()
+	current, ok := a.values[p]
+	load := a.loaders[p]
+	a.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	reloaded := loadAsync(load)
+
+	poll = func() (bool, error) {
+		value, err, done := reloaded.Poll()
+		if !done || err != nil {
+			return done, err
+		}
+
+		if previous, err, ok := current.Poll(); ok && err == nil && swapAssetInPlace(previous, value) {
+			return true, nil
+		}
+
+		current.value.Store(&value)
+		return true, nil
+	}
+
+	return poll, true
+}
+
+// Reloadable is implemented by assets that can be replaced in place after they
+// were reloaded, so that all existing users of the asset see the new version.
+// Other assets are only replaced in the asset cache, users holding on to the
+// previous value need to react to the AssetModified message.
+type Reloadable interface {
+	// ReplaceWith takes over the contents of the reloaded value, which has the same
+	// type as the receiver, and releases the resources of the previous contents.
+	ReplaceWith(reloaded any)
+}
+
+// swapAssetInPlace replaces previous with the reloaded value, if previous implements Reloadable.
+// Returns false, if previous is not Reloadable or the values are of different types.
+func swapAssetInPlace(previous, reloaded any) bool {
+	reloadable, ok := previous.(Reloadable)
+	if !ok || reflect.TypeOf(previous) != reflect.TypeOf(reloaded) {
+		return false
+	}
+
+	reloadable.ReplaceWith(reloaded)
+
+	return true
+}
+
 func readSeekerOf(r io.ReadCloser) io.ReadSeekCloser {
 	if rs, ok := r.(io.ReadSeekCloser); ok {
 		return rs
@@ -72,6 +72,11 @@ func (a *AudioSource) NewStream() audio.Stream[float32] {
 	return a.factory()
 }
 
+// ReplaceWith implements Reloadable. Streams created before keep playing the previous version.
+func (a *AudioSource) ReplaceWith(reloaded any) {
+	*a = *reloaded.(*AudioSource)
+}
+
 type AudioPlayer struct {
 	byke.ImmutableComponent[AudioPlayer]
 	Source *AudioSource
@@ -3,7 +3,7 @@ package byke2d
 import (
 	_ "embed"
 
-	"github.com/oliverbestmann/puffin-go"
+	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -90,7 +90,7 @@ func BlitTextureSimple(
 }
 
 func BlitTexture(ctx *RenderContext, enc *CommandEncoder, pipeline Pipeline, sampler *wgpu.Sampler, sourceView, targetView *wgpu.TextureView) {
-	defer puffin.NewScope("byke2d.blitTexture").End()
+	defer byke.NewProfileScope("byke2d.blitTexture").End()
 
 	bindGroup := ctx.CreateBindGroup(&wgpu.BindGroupDescriptor{
 		Label:  "Blit",
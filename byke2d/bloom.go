@@ -11,7 +11,6 @@ import (
 	"github.com/oliverbestmann/byke/byke2d/glm"
 	"github.com/oliverbestmann/byke/byke2d/wgsl"
 	"github.com/oliverbestmann/byke/spoke"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -308,7 +307,7 @@ func applyBloomSystem(
 		return
 	}
 
-	defer puffin.NewScope("byke2d.Bloom").End()
+	defer byke.NewProfileScope("byke2d.Bloom").End()
 
 	isUniformScale := view.Bloom.Scale == glm.Vec2f{1, 1}
 
@@ -45,6 +45,10 @@ func pluginDebug(app *byke.App) {
 		RunIf(KeyIsJustPressed(vyn.KeyD)).
 		RunIf(KeyIsPressed(vyn.KeyShiftLeft)))
 
+	app.AddSystems(byke.Update, byke.System(dumpProfilerTraceSystem).
+		RunIf(KeyIsJustPressed(vyn.KeyF12)).
+		RunIf(KeyIsPressed(vyn.KeyShiftLeft)))
+
 	app.InitState(DebugStateOff)
 
 	app.AddSystems(byke.OnEnter(DebugStateOn), setupDebugCameraSystem)
@@ -99,6 +103,24 @@ func printCameraGlobalPositionSystem(
 	}
 }
 
+// dumpProfilerTraceSystem writes the frames recorded by the worlds
+// byke.Profiler into a Chrome trace file.
+func dumpProfilerTraceSystem(world *byke.World) {
+	profiler := world.Profiler()
+	if profiler == nil {
+		slog.Warn("Profiler is not enabled, add byke.PluginProfiler to record a trace")
+		return
+	}
+
+	path, err := profiler.Dump()
+	if err != nil {
+		slog.Warn("Failed to dump trace", slog.String("err", err.Error()))
+		return
+	}
+
+	slog.Info("Trace written", slog.String("path", path))
+}
+
 func toggleDebugStateSystem(
 	state byke.State[DebugState],
 	nextState *byke.NextState[DebugState],
@@ -0,0 +1,126 @@
+package byke2d
+
+import (
+	"math"
+	"strings"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/oliverbestmann/byke/byke2d/glm"
+)
+
+var _ = byke.ValidateComponent[diagnosticsOverlayText]()
+
+// Diagnostics measured by PluginRenderDiagnostics.
+const (
+	DiagnosticAssetLoads       byke.DiagnosticPath = "asset_loads"
+	DiagnosticAssetsLoading    byke.DiagnosticPath = "assets_loading"
+	DiagnosticDrawCalls        byke.DiagnosticPath = "draw_calls"
+	DiagnosticMeshVertexMemory byke.DiagnosticPath = "mesh_vertex_memory"
+	DiagnosticMeshIndexMemory  byke.DiagnosticPath = "mesh_index_memory"
+	DiagnosticMeshMorphMemory  byke.DiagnosticPath = "mesh_morph_memory"
+)
+
+const diagnosticsOverlayRenderLayer = 30
+
+// PluginRenderDiagnostics adds measurements of asset loading, draw calls and
+// GPU memory used by meshes to the byke.Diagnostics.
+// Requires byke.PluginDiagnostics and PluginRender.
+func PluginRenderDiagnostics(app *byke.App) {
+	diagnostics := app.World().RequireResourceOf[byke.Diagnostics]()
+	diagnostics.Register(DiagnosticAssetLoads, "")
+	diagnostics.Register(DiagnosticAssetsLoading, "")
+	diagnostics.Register(DiagnosticDrawCalls, "")
+	diagnostics.Register(DiagnosticMeshVertexMemory, "kb")
+	diagnostics.Register(DiagnosticMeshIndexMemory, "kb")
+	diagnostics.Register(DiagnosticMeshMorphMemory, "kb")
+
+	app.AddSystems(byke.Last, measureRenderDiagnosticsSystem)
+}
+
+func measureRenderDiagnosticsSystem(
+	diagnostics *byke.Diagnostics,
+	ctx *RenderContext,
+	assets *Assets,
+	alloc *MeshAllocator,
+) {
+	diagnostics.Add(DiagnosticAssetLoads, float64(assets.FinishCount()))
+	diagnostics.Add(DiagnosticAssetsLoading, float64(assets.StartCount()-assets.FinishCount()))
+	diagnostics.Add(DiagnosticDrawCalls, float64(ctx.Metrics.Draw+ctx.Metrics.DrawIndexed))
+
+	stats := alloc.Stats()
+	diagnostics.Add(DiagnosticMeshVertexMemory, float64(stats.Vertices)/1024)
+	diagnostics.Add(DiagnosticMeshIndexMemory, float64(stats.Indices)/1024)
+	diagnostics.Add(DiagnosticMeshMorphMemory, float64(stats.MorphAttributes)/1024)
+}
+
+// DiagnosticsOverlay is a byke.DiagnosticsSink that shows the diagnostics
+// in the top left corner of the window. Add it using PluginDiagnosticsOverlay.
+type DiagnosticsOverlay struct {
+	text string
+}
+
+func (o *DiagnosticsOverlay) Report(diagnostics *byke.Diagnostics) {
+	var out strings.Builder
+
+	for _, diagnostic := range diagnostics.All() {
+		out.WriteString(diagnostic.String())
+		out.WriteByte('\n')
+	}
+
+	o.text = out.String()
+}
+
+type diagnosticsOverlayText struct {
+	byke.ImmutableComponent[diagnosticsOverlayText]
+}
+
+// PluginDiagnosticsOverlay shows the byke.Diagnostics in game.
+// Requires byke.PluginDiagnostics.
+func PluginDiagnosticsOverlay(app *byke.App) {
+	app.InsertResource(DiagnosticsOverlay{})
+
+	overlay := app.World().RequireResourceOf[DiagnosticsOverlay]()
+	app.World().RequireResourceOf[byke.Diagnostics]().AddSink(overlay)
+
+	app.AddSystems(byke.Startup, setupDiagnosticsOverlaySystem)
+	app.AddSystems(byke.Update, updateDiagnosticsOverlaySystem)
+}
+
+func setupDiagnosticsOverlaySystem(commands *byke.Commands) {
+	commands.Spawn(
+		Camera{Order: math.MaxInt - 1},
+		Camera2d,
+		RenderLayersOf(diagnosticsOverlayRenderLayer),
+		ClearColor{Color: ColorSRGBA(0, 0, 0, 0)},
+		OrthographicProjection{
+			ViewportOrigin: glm.Vec2f{0, 0},
+			ScalingMode:    ScalingModeFixedVertical{ViewportHeight: 600},
+		},
+	)
+
+	commands.Spawn(
+		diagnosticsOverlayText{},
+		DefaultFontMono(),
+		RenderLayersOf(diagnosticsOverlayRenderLayer),
+		AnchorTopLeft,
+		TransformFromXY(16, 600),
+		Text{
+			Color: ColorSRGB(1.0, 1.0, 1.0),
+			Size:  12.0,
+		},
+	)
+}
+
+func updateDiagnosticsOverlaySystem(
+	overlay *DiagnosticsOverlay,
+	query byke.Query[struct {
+		_    byke.With[diagnosticsOverlayText]
+		Text *Text
+	}],
+) {
+	for item := range query.Items() {
+		if item.Text.Text != overlay.text {
+			item.Text.Text = overlay.text
+		}
+	}
+}
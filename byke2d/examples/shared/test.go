@@ -11,6 +11,7 @@ import (
 	"os"
 	"runtime"
 	"slices"
+	"time"
 
 	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d"
@@ -62,6 +63,12 @@ func RunAppInTest(app byke.App, framesToSnapshot FramesToSnapshot) {
 		},
 	})
 
+	// advance time by exactly 1/60th of a second per frame
+	app.InsertResource(byke.FakeClock{
+		Now:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Step: time.Second / 60,
+	})
+
 	// force fallback adapter
 	_ = os.Setenv("WGPU_FORCE_FALLBACK_ADAPTER", "1")
 
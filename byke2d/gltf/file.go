@@ -428,6 +428,12 @@ type Handle struct {
 	buffers [][]byte
 }
 
+// ReplaceWith replaces the content of the handle with the content of
+// the reloaded *Handle, so existing users see the new version of the file.
+func (h *Handle) ReplaceWith(reloaded any) {
+	*h = *reloaded.(*Handle)
+}
+
 func GLTF(fileSystem fs.FS, r io.Reader) (*Handle, error) {
 	// parse the json chunk
 	var content fileContent
@@ -27,6 +27,16 @@ func (h *Hash) Pointer[T any](value *T) {
 	h.Update(uint64(uintptr(unsafe.Pointer(value))))
 }
 
+// Texture hashes the identity of a texture. The hash changes if the
+// texture is modified in place, e.g. by a hot reload.
+func (h *Hash) Texture(texture *Texture) {
+	h.Pointer(texture)
+
+	if texture != nil {
+		h.Pointer(texture.TextureView)
+	}
+}
+
 func (h *Hash) Float32(value float32) {
 	h.Update(uint64(math.Float32bits(value)))
 }
@@ -88,7 +88,7 @@ func (m ColorMaterial) WriteUniforms(w *wgsl.StructWriter) {
 
 func (m ColorMaterial) BindGroupKey() MaterialBindGroupKey {
 	var hash Hash = 0xEA55D3ABE75DF54F
-	hash.Pointer(m.Texture)
+	hash.Texture(m.Texture)
 	hash.Int(m.MaterialValues.BindGroupKey())
 	return MaterialBindGroupKey(hash)
 }
@@ -193,11 +193,11 @@ func (m StandardMaterial) WriteUniforms(w *wgsl.StructWriter) {
 
 func (m StandardMaterial) BindGroupKey() MaterialBindGroupKey {
 	var hash Hash = 0xC2ACE5D3D65CE2C6
-	hash.Pointer(m.Texture)
-	hash.Pointer(m.EmissiveTexture)
-	hash.Pointer(m.NormalTexture)
-	hash.Pointer(m.OcclusionTexture)
-	hash.Pointer(m.RoughnessMetallicTexture)
+	hash.Texture(m.Texture)
+	hash.Texture(m.EmissiveTexture)
+	hash.Texture(m.NormalTexture)
+	hash.Texture(m.OcclusionTexture)
+	hash.Texture(m.RoughnessMetallicTexture)
 	hash.Int(m.MaterialValues.BindGroupKey())
 	return MaterialBindGroupKey(hash)
 }
@@ -35,9 +35,18 @@ type PipelineCache struct {
 	ctx       *RenderContext
 	shaders   *Shaders
 	pipelines meh.Map[PipelineConfig, Pipeline]
+
+	// version of the shaders the cached pipelines were created with
+	shadersVersion uint32
 }
 
 func (p *PipelineCache) Specialize(config PipelineConfig) Pipeline {
+	if p.shaders.version != p.shadersVersion {
+		// shaders were modified, pipelines need to be created again
+		p.pipelines = meh.Map[PipelineConfig, Pipeline]{}
+		p.shadersVersion = p.shaders.version
+	}
+
 	cached, ok := p.pipelines.Get(config)
 	if ok {
 		return cached
@@ -12,7 +12,6 @@ import (
 	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d/glm"
 	"github.com/oliverbestmann/byke/byke2d/vyn"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -55,7 +54,52 @@ var (
 	Core3dBlit           = &byke.SystemSet{Name: "Core3dBlit"}
 )
 
+// PluginRender adds all plugins of DefaultPlugins.
+//
+// The Assets resource is created when the plugin is added, using the AssetFS resource
+// if one exists, or the default assets directory. An AssetFS inserted by a plugin added
+// later is swapped into the Assets resource once all plugins were added. Assets loaded
+// before that are read from the previous AssetFS.
 func PluginRender(app *byke.App) {
+	app.AddPluginGroup(DefaultPlugins())
+}
+
+// DefaultPlugins returns a new group of all plugins needed to run a game. Individual
+// plugins can be disabled or replaced using their name before the group is added
+// using byke.App.AddPluginGroup:
+//
+//	app.AddPluginGroup(byke2d.DefaultPlugins().Disable(byke2d.PluginNameFPV))
+func DefaultPlugins() *byke.PluginGroup {
+	return byke.NewPluginGroup(
+		RenderPlugin{},
+		byke.NewNamedPlugin(PluginNameDebug, pluginDebug),
+		byke.NewNamedPlugin(PluginNameGltf, pluginGltf),
+		byke.NewNamedPlugin(PluginNameAnimations, pluginAnimations),
+		byke.NewNamedPlugin(PluginNameFPV, pluginFPV),
+		byke.NewNamedPlugin(PluginNameSkybox, pluginSkybox),
+	)
+}
+
+// Names of the plugins in DefaultPlugins.
+const (
+	PluginNameRender     = "byke2d.Render"
+	PluginNameDebug      = "byke2d.Debug"
+	PluginNameGltf       = "byke2d.Gltf"
+	PluginNameAnimations = "byke2d.Animations"
+	PluginNameFPV        = "byke2d.FPV"
+	PluginNameSkybox     = "byke2d.Skybox"
+)
+
+// RenderPlugin sets up the window, rendering, input, audio and asset loading.
+type RenderPlugin struct {
+	byke.PluginDefaults
+}
+
+func (RenderPlugin) Name() string {
+	return PluginNameRender
+}
+
+func (RenderPlugin) Build(app *byke.App) {
 	assetFs, ok := app.World().ResourceOf[AssetFS]()
 	if !ok {
 		app.InsertResource(initializeAssetFS())
@@ -71,6 +115,8 @@ func PluginRender(app *byke.App) {
 	app.InsertResource(surfaceConfigState{})
 
 	app.AddPlugin(pluginShader)
+	app.AddPlugin(pluginAssets)
+	app.AddPlugin(pluginPrefabAssets)
 
 	app.InitResource(PipelineCacheFromWorld)
 	app.InitResource(TextureCacheFromWorld)
@@ -162,16 +208,16 @@ func PluginRender(app *byke.App) {
 	app.AddPlugin(pluginMesh)
 	app.AddPlugin(pluginMesh3d)
 
-	app.AddPlugin(pluginDebug)
-	app.AddPlugin(pluginGltf)
-	app.AddPlugin(pluginAnimations)
-
-	app.AddPlugin(pluginFPV)
-	app.AddPlugin(pluginSkybox)
-
 	app.RunWorld(runWorld)
 }
 
+// Finish swaps an AssetFS inserted by a plugin added after the RenderPlugin
+// into the Assets resource.
+func (RenderPlugin) Finish(app *byke.App) {
+	assets := app.World().RequireResourceOf[Assets]()
+	assets.fs = app.World().RequireResourceOf[AssetFS]().FS
+}
+
 func MakeAssets(world *byke.World, fs fs.FS) Assets {
 	return makeAssets(
 		world, fs,
@@ -180,6 +226,7 @@ func MakeAssets(world *byke.World, fs fs.FS) Assets {
 		TextureLoader{},
 		AudioLoader{},
 		GLTFLoader{},
+		PrefabLoader{},
 	)
 }
 
@@ -374,7 +421,7 @@ type currentSurfaceValues struct {
 }
 
 func updateWorld(world *byke.World, makeInputState vyn.UpdateInputState) error {
-	defer puffin.NewScope("frame").End()
+	defer byke.NewProfileScope("frame").End()
 
 	ctx, _ := world.ResourceOf[RenderContext]()
 	win, _ := world.ResourceOf[PrimaryWindow]()
@@ -417,7 +464,7 @@ func ensureSurfaceConfigured(ctx *RenderContext, world *byke.World, surfaceWidth
 		return
 	}
 
-	defer puffin.NewScope("surface.Configure").End()
+	defer byke.NewProfileScope("surface.Configure").End()
 
 	slog.Debug(
 		"Configure surface",
@@ -477,7 +524,7 @@ func renderMainSystem(
 ) {
 	// get the surface texture (the actual screen)
 	surfaceTexture := func() wgpu.SurfaceTexture {
-		defer puffin.NewScope("surface.GetCurrentTexture").End()
+		defer byke.NewProfileScope("surface.GetCurrentTexture").End()
 		return ctx.Surface.GetCurrentTexture()
 	}()
 
@@ -523,10 +570,9 @@ func renderMainSystem(
 	world.RunSchedule(PostRender)
 
 	// present the current frame
-	puffin.Scoped("surface.Present", func() any {
-		ctx.Surface.Present()
-		return nil
-	})
+	scope := byke.NewProfileScope("surface.Present")
+	ctx.Surface.Present()
+	scope.End()
 }
 
 type offscreenSurface struct {
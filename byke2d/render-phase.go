@@ -6,7 +6,6 @@ import (
 
 	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d/radix"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -254,7 +253,7 @@ func sortRenderPhasesSystem[M any](phases byke.Query[*SortableRenderPhase[M]]) {
 }
 
 func sortRenderPhase[M any](phase *SortableRenderPhase[M]) {
-	defer puffin.NewScope("Sort RenderPhase").End()
+	defer byke.NewProfileScope("Sort RenderPhase").End()
 
 	n := len(phase.sortValues)
 	if n == 0 {
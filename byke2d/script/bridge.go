@@ -0,0 +1,351 @@
+package script
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/dop251/goja"
+	"github.com/oliverbestmann/byke"
+)
+
+// newScriptObject creates the byke object passed to a script during evaluation.
+// Systems added by the script are appended to systems.
+func (r *Runtime) newScriptObject(loaded *loadedScript, systems *[]*scriptSystem) *goja.Object {
+	obj := r.vm.NewObject()
+
+	_ = obj.Set("state", loaded.State)
+
+	_ = obj.Set("addSystem", func(call goja.FunctionCall) goja.Value {
+		schedule := call.Argument(0).String()
+		if _, ok := r.schedules[schedule]; !ok {
+			panic(r.vm.NewTypeError("unknown schedule %q", schedule))
+		}
+
+		callable, ok := goja.AssertFunction(call.Argument(1))
+		if !ok {
+			panic(r.vm.NewTypeError("system must be a function"))
+		}
+
+		*systems = append(*systems, &scriptSystem{
+			Schedule:       schedule,
+			Callable:       callable,
+			lastMessageIds: map[string]byke.MessageId{},
+		})
+
+		return goja.Undefined()
+	})
+
+	_ = obj.Set("log", func(call goja.FunctionCall) goja.Value {
+		var args []any
+		for _, arg := range call.Arguments {
+			args = append(args, arg.Export())
+		}
+
+		slog.Info(fmt.Sprint(args...), slog.String("script", loaded.Path))
+		return goja.Undefined()
+	})
+
+	return obj
+}
+
+// systemContext is the world object passed to a script system.
+type systemContext struct {
+	runtime  *Runtime
+	world    *byke.World
+	system   *scriptSystem
+	commands commandQueue
+}
+
+func (c *systemContext) object() *goja.Object {
+	vm := c.runtime.vm
+
+	obj := vm.NewObject()
+	_ = obj.Set("query", c.query)
+	_ = obj.Set("resource", c.resource)
+	_ = obj.Set("readMessages", c.readMessages)
+	_ = obj.Set("writeMessage", c.writeMessage)
+
+	commands := vm.NewObject()
+	_ = commands.Set("spawn", c.spawn)
+	_ = commands.Set("despawn", c.despawn)
+	_ = commands.Set("insert", c.insert)
+	_ = commands.Set("remove", c.remove)
+	_ = obj.Set("commands", commands)
+
+	return obj
+}
+
+// throw raises the error as an exception within the script.
+func (c *systemContext) throw(err error) {
+	panic(c.runtime.vm.NewGoError(err))
+}
+
+// rethrow raises an error returned by a script callback within the script,
+// keeping the original exception if there is one.
+func (c *systemContext) rethrow(err error) {
+	var exception *goja.Exception
+	if errors.As(err, &exception) {
+		panic(exception)
+	}
+
+	c.throw(err)
+}
+
+func (c *systemContext) componentTypesOf(value goja.Value) []*byke.ComponentType {
+	var names []string
+	if err := c.runtime.vm.ExportTo(value, &names); err != nil {
+		c.throw(fmt.Errorf("expected a list of component names: %w", err))
+	}
+
+	var componentTypes []*byke.ComponentType
+
+	for _, name := range names {
+		componentType, ok := c.world.TypeRegistry().ComponentType(name)
+		if !ok {
+			c.throw(fmt.Errorf("unknown component type %q", name))
+		}
+
+		componentTypes = append(componentTypes, componentType)
+	}
+
+	return componentTypes
+}
+
+// query calls the callback for each entity having all the given components.
+// The callback receives the entity id followed by the components. Modifications
+// of the components are written back after the system finished, like other commands.
+func (c *systemContext) query(call goja.FunctionCall) goja.Value {
+	vm := c.runtime.vm
+
+	componentTypes := c.componentTypesOf(call.Argument(0))
+
+	callback, ok := goja.AssertFunction(call.Argument(1))
+	if !ok {
+		c.throw(errors.New("query callback must be a function"))
+	}
+
+	for entity := range c.runtime.queryOf(c.world, componentTypes).Entities() {
+		entityId := entity.EntityId()
+
+		// scripts work on copies of the components
+		copies := make([]byke.ErasedComponent, len(componentTypes))
+		args := []goja.Value{vm.ToValue(int64(entityId))}
+
+		for idx, componentType := range componentTypes {
+			copies[idx] = componentType.CopyOf(entity.Get(componentType))
+			args = append(args, vm.ToValue(copies[idx]))
+		}
+
+		if _, err := callback(goja.Undefined(), args...); err != nil {
+			c.rethrow(err)
+		}
+
+		var changed []byke.ErasedComponent
+		for idx, componentType := range componentTypes {
+			if !reflect.DeepEqual(copies[idx], entity.Get(componentType)) {
+				changed = append(changed, copies[idx])
+			}
+		}
+
+		if len(changed) > 0 {
+			c.commands.push(func(world *byke.World) error {
+				return world.InsertComponents(entityId, changed...)
+			})
+		}
+	}
+
+	return goja.Undefined()
+}
+
+// queryOf returns a cached query for the given component types.
+// Like queries in systems, it does not match disabled entities.
+func (r *Runtime) queryOf(world *byke.World, componentTypes []*byke.ComponentType) *byke.DynamicQuery {
+	var key string
+	for _, componentType := range componentTypes {
+		key += componentType.Name + ","
+	}
+
+	query, ok := r.queries[key]
+	if !ok {
+		query = world.DynamicQuery(componentTypes...)
+		r.queries[key] = query
+	}
+
+	return query
+}
+
+// resource returns the resource with the given type name, e.g. "byke.VirtualTime".
+// The resource is returned by reference, modifications are visible to other systems.
+func (c *systemContext) resource(call goja.FunctionCall) goja.Value {
+	name := call.Argument(0).String()
+
+	for _, ty := range c.world.ResourceTypes() {
+		if ty.String() != name {
+			continue
+		}
+
+		value, _ := c.world.Resource(ty)
+		return c.runtime.vm.ToValue(value)
+	}
+
+	return goja.Null()
+}
+
+func (c *systemContext) messageBridge(name string) messageBridge {
+	bridge, ok := c.runtime.messages[name]
+	if !ok {
+		c.throw(fmt.Errorf("unknown message type %q, register it using script.RegisterMessage", name))
+	}
+
+	return bridge
+}
+
+// readMessages returns all messages of the given type written since the last
+// call of this system.
+func (c *systemContext) readMessages(call goja.FunctionCall) goja.Value {
+	name := call.Argument(0).String()
+	bridge := c.messageBridge(name)
+
+	lastId := c.system.lastMessageIds[name]
+	messages, lastId := bridge.Read(c.world, lastId)
+	c.system.lastMessageIds[name] = lastId
+
+	return c.runtime.vm.ToValue(messages)
+}
+
+func (c *systemContext) writeMessage(call goja.FunctionCall) goja.Value {
+	bridge := c.messageBridge(call.Argument(0).String())
+
+	if err := bridge.Write(c.world, call.Argument(1).Export()); err != nil {
+		c.throw(err)
+	}
+
+	return goja.Undefined()
+}
+
+// componentsOf decodes an object mapping component names to values.
+func (c *systemContext) componentsOf(entityId byke.EntityId, value goja.Value) []byke.ErasedComponent {
+	var values map[string]any
+	if err := c.runtime.vm.ExportTo(value, &values); err != nil {
+		c.throw(fmt.Errorf("expected an object of components: %w", err))
+	}
+
+	var components []byke.ErasedComponent
+
+	for name, value := range values {
+		componentType, ok := c.world.TypeRegistry().ComponentType(name)
+		if !ok {
+			c.throw(fmt.Errorf("unknown component type %q", name))
+		}
+
+		// apply the value on top of the current value of the component
+		var base byke.ErasedComponent
+		if entity, ok := c.world.Entity(entityId); ok && entity.Has(componentType) {
+			base = entity.Get(componentType)
+		}
+
+		component, err := decodeComponent(componentType, base, value)
+		if err != nil {
+			c.throw(err)
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
+
+func (c *systemContext) spawn(call goja.FunctionCall) goja.Value {
+	components := c.componentsOf(byke.NoEntityId, call.Argument(0))
+
+	c.commands.push(func(world *byke.World) error {
+		world.Spawn(components)
+		return nil
+	})
+
+	return goja.Undefined()
+}
+
+func (c *systemContext) despawn(call goja.FunctionCall) goja.Value {
+	entityId := byke.EntityId(call.Argument(0).ToInteger())
+
+	c.commands.push(func(world *byke.World) error {
+		return world.TryDespawn(entityId)
+	})
+
+	return goja.Undefined()
+}
+
+func (c *systemContext) insert(call goja.FunctionCall) goja.Value {
+	entityId := byke.EntityId(call.Argument(0).ToInteger())
+	components := c.componentsOf(entityId, call.Argument(1))
+
+	c.commands.push(func(world *byke.World) error {
+		return world.InsertComponents(entityId, components...)
+	})
+
+	return goja.Undefined()
+}
+
+func (c *systemContext) remove(call goja.FunctionCall) goja.Value {
+	entityId := byke.EntityId(call.Argument(0).ToInteger())
+	componentTypes := c.componentTypesOf(call.Argument(1))
+
+	c.commands.push(func(world *byke.World) error {
+		for _, componentType := range componentTypes {
+			if err := world.RemoveComponent(entityId, componentType); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return goja.Undefined()
+}
+
+// commandQueue collects the commands of a script system. Like byke.Commands,
+// they are applied after the system finished.
+type commandQueue []func(world *byke.World) error
+
+func (q *commandQueue) push(command func(world *byke.World) error) {
+	*q = append(*q, command)
+}
+
+func (q commandQueue) apply(world *byke.World) error {
+	var errs []error
+
+	for _, command := range q {
+		errs = append(errs, command(world))
+	}
+
+	return errors.Join(errs...)
+}
+
+// decodeComponent converts a value exported from the script into a component of
+// the given type. If base is not nil, the value is applied on top of a copy of base.
+func decodeComponent(componentType *byke.ComponentType, base byke.ErasedComponent, value any) (byke.ErasedComponent, error) {
+	component := componentType.New()
+	if base != nil {
+		component = componentType.CopyOf(base)
+	}
+
+	if err := convert(value, component); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", componentType.Name, err)
+	}
+
+	return component, nil
+}
+
+// convert converts a value exported from the script into target using its json representation.
+func convert(value any, target any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, target)
+}
@@ -0,0 +1,73 @@
+package script
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// messageBridge reads and writes messages of a type only known at runtime.
+type messageBridge interface {
+	// Read returns all messages with an id greater than lastId,
+	// together with the id of the last message returned.
+	Read(world *byke.World, lastId byke.MessageId) ([]any, byke.MessageId)
+
+	// Write converts the value into a message and sends it.
+	Write(world *byke.World, value any) error
+}
+
+// RegisterMessage makes messages of type E available to scripts, using the
+// name of the type, e.g. "main.PlayerDied". The message must be added
+// to the app using byke.App.AddMessage. Requires Plugin.
+func RegisterMessage[E any](app *byke.App) {
+	runtime := app.World().RequireResourceOf[Runtime]()
+	runtime.messages[reflect.TypeFor[E]().String()] = typedMessageBridge[E]{}
+}
+
+type typedMessageBridge[E any] struct{}
+
+func (typedMessageBridge[E]) messagesOf(world *byke.World) (*byke.Messages[E], error) {
+	messages, ok := world.ResourceOf[byke.Messages[E]]()
+	if !ok {
+		return nil, fmt.Errorf("message %s was not added to the app", reflect.TypeFor[E]())
+	}
+
+	return messages, nil
+}
+
+func (b typedMessageBridge[E]) Read(world *byke.World, lastId byke.MessageId) ([]any, byke.MessageId) {
+	messages, err := b.messagesOf(world)
+	if err != nil {
+		return nil, lastId
+	}
+
+	var values []any
+
+	for _, message := range messages.AppendTo(nil) {
+		if message.Id <= lastId {
+			continue
+		}
+
+		values = append(values, message.Message)
+		lastId = message.Id
+	}
+
+	return values, lastId
+}
+
+func (b typedMessageBridge[E]) Write(world *byke.World, value any) error {
+	messages, err := b.messagesOf(world)
+	if err != nil {
+		return err
+	}
+
+	var message E
+	if err := convert(value, &message); err != nil {
+		return fmt.Errorf("decode message %s: %w", reflect.TypeFor[E](), err)
+	}
+
+	messages.Send(message)
+
+	return nil
+}
@@ -0,0 +1,273 @@
+// Package script adds support for gameplay systems written in JavaScript.
+//
+// A script is evaluated once it is loaded and registers its systems using the
+// global-like byke object passed to it:
+//
+//	byke.state.counter ??= 0
+//
+//	byke.addSystem("Update", (world) => {
+//		world.query(["byke2d.Transform", "main.Velocity"], (entity, transform, velocity) => {
+//			transform.Translation[0] += velocity.X
+//		})
+//	})
+//
+// The object stored in byke.state survives a reload of the script file. Scripts are
+// reloaded when byke2d.PluginAssetHotReload reports a modification of the file.
+package script
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"reflect"
+	"slices"
+
+	"github.com/dop251/goja"
+	"github.com/oliverbestmann/byke"
+	"github.com/oliverbestmann/byke/byke2d"
+)
+
+// DefaultSchedules are the schedules scripts can add systems to.
+var DefaultSchedules = []byke.ScheduleId{
+	byke.First,
+	byke.PreUpdate,
+	byke.Update,
+	byke.PostUpdate,
+	byke.Last,
+	byke.FixedPreUpdate,
+	byke.FixedUpdate,
+	byke.FixedPostUpdate,
+}
+
+type Config struct {
+	// Paths of the scripts to load, relative to the byke2d.AssetFS.
+	Scripts []string
+
+	// Additional schedules scripts can add systems to, referenced by their name.
+	Schedules []byke.ScheduleId
+}
+
+// Plugin loads the configured scripts and runs the systems registered by them.
+// Requires byke2d.PluginRender for asset loading.
+func Plugin(config Config) byke.Plugin {
+	return func(app *byke.App) {
+		runtime := newRuntime()
+
+		schedules := append(slices.Clone(DefaultSchedules), config.Schedules...)
+		for _, scheduleId := range schedules {
+			runtime.schedules[scheduleId.String()] = scheduleId
+			app.AddSystems(scheduleId, runScriptSystems(scheduleId.String()))
+		}
+
+		app.InsertResource(runtime)
+
+		app.AddSystems(byke.Startup, func(assets *byke2d.Assets, runtime *Runtime) {
+			assets.RegisterLoader(ScriptLoader{})
+
+			for _, path := range config.Scripts {
+				runtime.scripts = append(runtime.scripts, &loadedScript{
+					Path:    path,
+					State:   runtime.vm.NewObject(),
+					pending: assets.Load[*Script](path),
+				})
+			}
+		})
+
+		app.AddSystems(byke.First, evaluateLoadedScriptsSystem)
+
+		// AssetModified messages are only available if hot reloading is enabled
+		app.AddSystems(byke.First, byke.System(reloadModifiedScriptsSystem).
+			Before(evaluateLoadedScriptsSystem).
+			RunIf(byke.ResourceExists[byke.Messages[byke2d.AssetModified]]))
+	}
+}
+
+// Script is a compiled JavaScript file.
+type Script struct {
+	Path    string
+	program *goja.Program
+}
+
+func compileScript(path string, source []byte) (*Script, error) {
+	// wrap the script into a function, so each evaluation gets its own scope.
+	// The wrapper is kept on the first line to not change line numbers in errors.
+	wrapped := "(function(byke) {" + string(source) + "\n})"
+
+	program, err := goja.Compile(path, wrapped, false)
+	if err != nil {
+		return nil, fmt.Errorf("compile script %q: %w", path, err)
+	}
+
+	return &Script{Path: path, program: program}, nil
+}
+
+// ScriptLoader loads and compiles JavaScript files.
+type ScriptLoader struct{}
+
+func (ScriptLoader) Type() reflect.Type {
+	return reflect.TypeFor[*Script]()
+}
+
+func (ScriptLoader) Load(ctx byke2d.LoadContext, r io.ReadSeekCloser) (any, error) {
+	defer func() { _ = r.Close() }()
+
+	source, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read script: %w", err)
+	}
+
+	return compileScript(ctx.Path, source)
+}
+
+func (ScriptLoader) Extensions() []string {
+	return []string{".js"}
+}
+
+// Runtime is the resource holding the JavaScript runtime and all loaded scripts.
+type Runtime struct {
+	vm        *goja.Runtime
+	scripts   []*loadedScript
+	schedules map[string]byke.ScheduleId
+	messages  map[string]messageBridge
+
+	// queries used by script systems, by component type names
+	queries map[string]*byke.DynamicQuery
+}
+
+func newRuntime() Runtime {
+	return Runtime{
+		vm:        goja.New(),
+		schedules: map[string]byke.ScheduleId{},
+		messages:  map[string]messageBridge{},
+		queries:   map[string]*byke.DynamicQuery{},
+	}
+}
+
+type loadedScript struct {
+	Path string
+
+	// State is passed to each evaluation of the script and survives reloads.
+	State *goja.Object
+
+	Systems []*scriptSystem
+
+	pending byke2d.AsyncAsset[*Script]
+}
+
+type scriptSystem struct {
+	Schedule string
+	Callable goja.Callable
+
+	// id of the last message read by this system, by message type
+	lastMessageIds map[string]byke.MessageId
+}
+
+// evaluate runs the script and replaces the systems of its previous version.
+// If the evaluation fails, the previous systems are kept.
+func (r *Runtime) evaluate(loaded *loadedScript, script *Script) error {
+	value, err := r.vm.RunProgram(script.program)
+	if err != nil {
+		return fmt.Errorf("run script %q: %w", script.Path, err)
+	}
+
+	fn, ok := goja.AssertFunction(value)
+	if !ok {
+		return fmt.Errorf("script %q did not evaluate to a function", script.Path)
+	}
+
+	var systems []*scriptSystem
+
+	if _, err := fn(goja.Undefined(), r.newScriptObject(loaded, &systems)); err != nil {
+		return fmt.Errorf("evaluate script %q: %w", script.Path, err)
+	}
+
+	loaded.Systems = systems
+
+	return nil
+}
+
+func evaluateLoadedScriptsSystem(runtime *Runtime) {
+	for _, loaded := range runtime.scripts {
+		if loaded.pending == nil {
+			continue
+		}
+
+		script, err, ok := loaded.pending.Poll()
+		if !ok {
+			continue
+		}
+
+		loaded.pending = nil
+
+		if err != nil {
+			slog.Warn("Failed to load script", slog.String("path", loaded.Path), slog.String("err", err.Error()))
+			continue
+		}
+
+		if err := runtime.evaluate(loaded, script); err != nil {
+			slog.Warn("Failed to evaluate script", slog.String("path", loaded.Path), slog.String("err", err.Error()))
+		}
+	}
+}
+
+// reloadModifiedScriptsSystem evaluates scripts again after their file was reloaded.
+// The reloaded script replaces the previous one in the asset cache.
+func reloadModifiedScriptsSystem(
+	runtime *Runtime,
+	assets *byke2d.Assets,
+	reader *byke.MessageReader[byke2d.AssetModified],
+) {
+	for _, modified := range reader.Read() {
+		for _, loaded := range runtime.scripts {
+			if path.Clean(loaded.Path) != modified.Path {
+				continue
+			}
+
+			slog.Info("Script was modified, evaluating again", slog.String("path", loaded.Path))
+			loaded.pending = assets.Load[*Script](loaded.Path)
+		}
+	}
+}
+
+// runScriptSystems returns a system that runs all script systems registered for
+// the given schedule. A new closure is returned for each schedule, as a system
+// can only be added once to the app.
+func runScriptSystems(schedule string) func(world *byke.World) {
+	return func(world *byke.World) {
+		runtime, ok := world.ResourceOf[Runtime]()
+		if !ok {
+			return
+		}
+
+		for _, loaded := range runtime.scripts {
+			for _, system := range loaded.Systems {
+				if system.Schedule != schedule {
+					continue
+				}
+
+				if err := runtime.runSystem(world, system); err != nil {
+					slog.Warn("Script system failed",
+						slog.String("path", loaded.Path),
+						slog.String("schedule", schedule),
+						slog.String("err", err.Error()))
+				}
+			}
+		}
+	}
+}
+
+func (r *Runtime) runSystem(world *byke.World, system *scriptSystem) error {
+	ctx := &systemContext{
+		runtime: r,
+		world:   world,
+		system:  system,
+	}
+
+	if _, err := system.Callable(goja.Undefined(), ctx.object()); err != nil {
+		return err
+	}
+
+	// apply the commands only if the system succeeded
+	return ctx.commands.apply(world)
+}
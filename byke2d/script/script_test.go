@@ -0,0 +1,160 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+type Health struct {
+	byke.Component[Health]
+	Current int
+	Max     int
+}
+
+type Scored struct {
+	Points int
+}
+
+func TestDecodeComponent(t *testing.T) {
+	componentType := Health{}.ComponentType()
+
+	// fields missing in the value keep the value of base
+	base := &Health{Current: 5, Max: 10}
+	component, err := decodeComponent(componentType, base, map[string]any{"Current": 7})
+	require.NoError(t, err)
+	require.Equal(t, &Health{Current: 7, Max: 10}, component)
+
+	// base itself is not modified
+	require.Equal(t, &Health{Current: 5, Max: 10}, base)
+
+	component, err = decodeComponent(componentType, nil, map[string]any{"Max": 3})
+	require.NoError(t, err)
+	require.Equal(t, &Health{Max: 3}, component)
+
+	_, err = decodeComponent(componentType, nil, map[string]any{"Max": "three"})
+	require.Error(t, err)
+
+	var scored Scored
+	require.NoError(t, convert(map[string]any{"Points": int64(3)}, &scored))
+	require.Equal(t, Scored{Points: 3}, scored)
+}
+
+func TestMessageBridgeCursor(t *testing.T) {
+	var app byke.App
+	app.AddMessage[Scored]()
+
+	world := app.World()
+	messages := world.RequireResourceOf[byke.Messages[Scored]]()
+
+	var bridge typedMessageBridge[Scored]
+
+	messages.Send(Scored{Points: 1})
+	messages.Send(Scored{Points: 2})
+
+	values, lastId := bridge.Read(world, 0)
+	require.Equal(t, []any{Scored{Points: 1}, Scored{Points: 2}}, values)
+
+	// messages are not read twice, even after the buffers were swapped
+	messages.Update()
+	messages.Send(Scored{Points: 3})
+
+	values, lastId = bridge.Read(world, lastId)
+	require.Equal(t, []any{Scored{Points: 3}}, values)
+
+	values, _ = bridge.Read(world, lastId)
+	require.Empty(t, values)
+
+	require.NoError(t, bridge.Write(world, map[string]any{"Points": 4}))
+
+	values, _ = bridge.Read(world, lastId)
+	require.Equal(t, []any{Scored{Points: 4}}, values)
+}
+
+func mustCompile(t *testing.T, source string) *Script {
+	t.Helper()
+
+	script, err := compileScript("test.js", []byte(source))
+	require.NoError(t, err)
+
+	return script
+}
+
+func TestStateSurvivesEvaluate(t *testing.T) {
+	runtime := newRuntime()
+	runtime.schedules["Update"] = byke.Update
+
+	loaded := &loadedScript{Path: "test.js", State: runtime.vm.NewObject()}
+
+	source := `
+		byke.state.evaluations = (byke.state.evaluations ?? 0) + 1
+		byke.addSystem("Update", () => {})
+	`
+
+	require.NoError(t, runtime.evaluate(loaded, mustCompile(t, source)))
+	require.NoError(t, runtime.evaluate(loaded, mustCompile(t, source)))
+
+	require.Equal(t, int64(2), loaded.State.Get("evaluations").ToInteger())
+	require.Len(t, loaded.Systems, 1)
+
+	// a failing evaluation keeps the previous systems
+	require.Error(t, runtime.evaluate(loaded, mustCompile(t, `throw new Error("broken")`)))
+	require.Len(t, loaded.Systems, 1)
+}
+
+func TestQuerySkipsDisabledEntities(t *testing.T) {
+	world := byke.NewWorld()
+
+	enabled := world.Spawn([]byke.ErasedComponent{Health{Current: 1}})
+	world.Spawn([]byke.ErasedComponent{Health{Current: 2}, byke.Disabled{}})
+
+	runtime := newRuntime()
+
+	script := mustCompile(t, `
+		byke.addSystem("Update", (world) => {
+			world.query(["script.Health"], (entity, health) => {
+				byke.state.entities = [...(byke.state.entities ?? []), entity]
+				health.Current += 10
+			})
+		})
+	`)
+
+	runtime.schedules["Update"] = byke.Update
+
+	loaded := &loadedScript{Path: "test.js", State: runtime.vm.NewObject()}
+	require.NoError(t, runtime.evaluate(loaded, script))
+	require.NoError(t, runtime.runSystem(world, loaded.Systems[0]))
+
+	var entities []int64
+	require.NoError(t, runtime.vm.ExportTo(loaded.State.Get("entities"), &entities))
+	require.Equal(t, []int64{int64(enabled)}, entities)
+
+	entity, _ := world.Entity(enabled)
+	require.Equal(t, 11, entity.Get(Health{}.ComponentType()).(*Health).Current)
+}
+
+func TestFailingQueryCallbackFailsSystem(t *testing.T) {
+	world := byke.NewWorld()
+	entityId := world.Spawn([]byke.ErasedComponent{Health{Current: 1}})
+
+	runtime := newRuntime()
+	runtime.schedules["Update"] = byke.Update
+
+	script := mustCompile(t, `
+		byke.addSystem("Update", (world) => {
+			world.query(["script.Health"], (entity, health) => {
+				health.Current += 10
+				throw new Error("broken")
+			})
+		})
+	`)
+
+	loaded := &loadedScript{Path: "test.js", State: runtime.vm.NewObject()}
+	require.NoError(t, runtime.evaluate(loaded, script))
+	require.ErrorContains(t, runtime.runSystem(world, loaded.Systems[0]), "broken")
+
+	// modifications of a failed system are not written back
+	entity, _ := world.Entity(entityId)
+	require.Equal(t, 1, entity.Get(Health{}.ComponentType()).(*Health).Current)
+}
@@ -32,11 +32,34 @@ func pluginShader(app *byke.App) {
 type Shaders struct {
 	transpiler *wesl.Transpiler
 	files      map[string]string
+
+	// paths of shader files added using AddAsset, by name
+	assetPaths map[string]string
+
+	// incremented each time a shader file is added or replaced
+	version uint32
 }
 
 func (s *Shaders) Add(name, source string) {
 	ensureMapIsInitialized(&s.files)
 	s.files[name] = source
+	s.version += 1
+}
+
+// AddAsset adds a shader file from the AssetFS. The file is
+// reloaded by PluginAssetHotReload if it is modified.
+func (s *Shaders) AddAsset(assets *Assets, name, path string) error {
+	source, err := fs.ReadFile(assets.fs, path)
+	if err != nil {
+		return fmt.Errorf("read shader %q: %w", path, err)
+	}
+
+	s.Add(name, string(source))
+
+	ensureMapIsInitialized(&s.assetPaths)
+	s.assetPaths[name] = path
+
+	return nil
 }
 
 func (s *Shaders) Get() wesl.Files {
@@ -48,8 +48,10 @@ type metaSprites struct {
 
 type spriteRenderPhaseItem struct{}
 
+// spriteTextureBindGroupCache caches bind groups by texture view, so a texture
+// that is modified in place gets a new bind group.
 type spriteTextureBindGroupCache struct {
-	tickCache[*Texture, *wgpu.BindGroup]
+	tickCache[*wgpu.TextureView, *wgpu.BindGroup]
 }
 
 func prepareSpriteBindGroupsSystem(
@@ -103,9 +105,9 @@ func prepareSpriteBindGroupsSystem(
 				current.BatchCount = 0
 
 				// ensure bindgroup for image exists
-				if _, ok := bindGroups.Get(itemSprite.Texture); !ok {
+				if _, ok := bindGroups.Get(itemSprite.Texture.TextureView); !ok {
 					bindGroups.Add(
-						itemSprite.Texture,
+						itemSprite.Texture.TextureView,
 						ctx.CreateBindGroup(&wgpu.BindGroupDescriptor{
 							Label:  "Sprite Texture",
 							Layout: ctx.CreateBindGroupLayout(layoutSpriteTextures),
@@ -220,7 +222,7 @@ func drawSpriteBatchSystem(
 	})
 
 	// get the bind group for the texture for this batch
-	textureBindGroup, _ := textureBindGroups.Get(sprite.Texture)
+	textureBindGroup, _ := textureBindGroups.Get(sprite.Texture.TextureView)
 
 	pass.SetPipeline(pipeline.Get())
 	pass.SetBindGroup(0, viewBindGroup.BindGroup, []uint32{view.ViewUniformsOffset.Offset})
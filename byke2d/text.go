@@ -18,7 +18,6 @@ import (
 	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d/glm"
 	"github.com/oliverbestmann/byke/spoke"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 	"golang.org/x/image/math/fixed"
 )
@@ -67,13 +66,19 @@ type textCache struct {
 func cacheTextSystem(
 	ctx *RenderContext,
 	textQuery byke.Query[struct {
-		_     byke.Changed[Text]
-		Text  Text
-		Font  Font
-		Cache *textCache
+		Text      Text
+		TextTicks byke.Ticks[Text]
+		Font      Font
+		FontTicks byke.Ticks[Font]
+		Cache     *textCache
 	}],
 ) {
 	for item := range textQuery.Items() {
+		// layout again if either the text or the font has changed
+		if !item.TextTicks.IsChanged() && !item.FontTicks.IsChanged() {
+			continue
+		}
+
 		text := []rune(item.Text.Text)
 		layout := layoutText(text, item.Font.Faces, item.Text.Size)
 
@@ -181,7 +186,7 @@ type lineLayout struct {
 }
 
 func layoutText(text []rune, faces Faces, fontSize float32) textLayout {
-	defer puffin.NewScope("text.Layout").End()
+	defer byke.NewProfileScope("text.Layout").End()
 
 	lines := splitTextToLines(text)
 
@@ -276,7 +281,7 @@ func layoutLine(text []rune, line lineIndices, faces Faces, fontSize float32) li
 }
 
 func cacheGlyphs(ctx *RenderContext, fontSize float32, input shaping.Input, output shaping.Output) {
-	defer puffin.NewScope("text.CacheGlyphs").End()
+	defer byke.NewProfileScope("text.CacheGlyphs").End()
 
 	renderer := render.Renderer{
 		Color:    color.White,
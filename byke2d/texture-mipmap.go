@@ -4,7 +4,7 @@ import (
 	_ "embed"
 	"math/bits"
 
-	"github.com/oliverbestmann/puffin-go"
+	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -29,7 +29,7 @@ func (m *mipmapGenerator) Generate(texture *Texture) {
 		return
 	}
 
-	defer puffin.NewScopeWithValue("texture.GenerateMipMaps", texture.Descriptor.Label).End()
+	defer byke.NewProfileScopeWithValue("texture.GenerateMipMaps", texture.Descriptor.Label).End()
 
 	enc := m.context.CreateCommandEncoder(&wgpu.CommandEncoderDescriptor{
 		Label: "Texture.MipMap.Encoder",
@@ -5,8 +5,8 @@ import (
 	"image"
 	"image/draw"
 
+	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d/glm"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -57,7 +57,7 @@ type WritePixelsOptions struct {
 //
 // Setting a mip level prevents the function from re-generating mipmaps automatically.
 func (t *Texture) WritePixelsToRect(ctx *RenderContext, opts ...WritePixelsOptions) {
-	defer puffin.NewScope("texture.WritePixels").End()
+	defer byke.NewProfileScope("texture.WritePixels").End()
 
 	region := glm.RectuFromXYWH(0, 0, t.Width(), t.Height())
 
@@ -132,6 +132,13 @@ func (t *Texture) Release() {
 	t.Texture.Release()
 }
 
+// ReplaceWith implements Reloadable. The resources of the previous texture are released.
+func (t *Texture) ReplaceWith(reloaded any) {
+	previous := *t
+	*t = *reloaded.(*Texture)
+	previous.Release()
+}
+
 type NewTexture2dOptions struct {
 	Label string
 
@@ -244,7 +251,7 @@ type TextureFromImageOptions struct {
 
 // NewTextureFromImage creates a new Texture from the given golang image.Image instance.
 func NewTextureFromImage(ctx *RenderContext, src image.Image, opts TextureFromImageOptions) *Texture {
-	defer puffin.NewScope("byke2d.NewTextureFromImage").End()
+	defer byke.NewProfileScope("byke2d.NewTextureFromImage").End()
 
 	return newTextureFromImagesRGBA(ctx, []image.Image{src}, TextureFromSourcesOptions{
 		Label:            opts.Label,
@@ -263,7 +270,7 @@ type TextureFromImagesOptions struct {
 
 // NewTextureFromImages creates a new 2d layer texture from the given golang image.Image sequence
 func NewTextureFromImages(ctx *RenderContext, sources []image.Image, opts TextureFromImagesOptions) *Texture {
-	defer puffin.NewScope("byke2d.NewTextureFromImages").End()
+	defer byke.NewProfileScope("byke2d.NewTextureFromImages").End()
 
 	return newTextureFromImagesRGBA(ctx, sources, TextureFromSourcesOptions{
 		Label:            opts.Label,
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/oliverbestmann/puffin-go"
+	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -48,7 +48,7 @@ func (t *TonemappingLutTextures) get(ctx *RenderContext, tex **LutTexture, label
 		return **tex
 	}
 
-	defer puffin.NewScope("LookupTable." + label).End()
+	defer byke.NewProfileScope("LookupTable." + label).End()
 
 	size := wgpu.Extent3D{
 		Width:              width,
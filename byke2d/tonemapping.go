@@ -6,7 +6,6 @@ import (
 	"github.com/oliverbestmann/byke"
 	"github.com/oliverbestmann/byke/byke2d/glm"
 	"github.com/oliverbestmann/byke/byke2d/wgsl"
-	"github.com/oliverbestmann/puffin-go"
 	"github.com/oliverbestmann/webgpu/wgpu"
 )
 
@@ -329,7 +328,7 @@ func tonemappingSystem(
 		ColorGradingOffset DynamicOffset[ColorGrading]
 	}],
 ) {
-	defer puffin.NewScope("Tonemapping").End()
+	defer byke.NewProfileScope("Tonemapping").End()
 
 	view := viewQuery.Get()
 
@@ -0,0 +1,123 @@
+package byke2d
+
+import (
+	"github.com/chewxy/math32"
+	"github.com/oliverbestmann/byke"
+	"github.com/oliverbestmann/byke/byke2d/glm"
+)
+
+// TransformFromAffine decomposes the given affine matrix into translation, rotation and scale.
+// The matrix must not contain any shear. Axes with a scale of zero do not carry any
+// rotation, the rotation is derived from the remaining axes.
+func TransformFromAffine(mat glm.Mat4f) Transform {
+	x := mat.Column(0).Truncate()
+	y := mat.Column(1).Truncate()
+	z := mat.Column(2).Truncate()
+
+	scale := glm.Vec3f{x.Length(), y.Length(), z.Length()}
+
+	// a negative determinant indicates a mirrored axis
+	if x.Dot(y.Cross(z)) < 0 {
+		scale[0] = -scale[0]
+	}
+
+	basis := rotationBasis([3]glm.Vec3f{x, y, z}, scale)
+	rotation := glm.QuatFromMat3(glm.Mat3f{basis[0], basis[1], basis[2]})
+
+	return Transform{
+		Translation: mat.Translation(),
+		Rotation:    rotation,
+		Scale:       scale,
+	}
+}
+
+// rotationBasis normalizes the given axes. Axes with a scale of zero
+// are reconstructed from the remaining axes, keeping the basis right-handed.
+func rotationBasis(axes [3]glm.Vec3f, scale glm.Vec3f) [3]glm.Vec3f {
+	var basis [3]glm.Vec3f
+	var valid []int
+
+	for idx := range 3 {
+		if scale[idx] != 0 {
+			basis[idx] = axes[idx].Scale(1 / scale[idx])
+			valid = append(valid, idx)
+		}
+	}
+
+	switch len(valid) {
+	case 3:
+		return basis
+
+	case 2:
+		missing := 3 - valid[0] - valid[1]
+		basis[missing] = basis[(missing+1)%3].Cross(basis[(missing+2)%3])
+		return basis
+
+	case 1:
+		idx := valid[0]
+
+		// any axis perpendicular to the remaining one will do
+		helper := glm.Vec3f{1, 0, 0}
+		if math32.Abs(basis[idx][0]) > 0.9 {
+			helper = glm.Vec3f{0, 1, 0}
+		}
+
+		basis[(idx+1)%3] = helper.Cross(basis[idx]).Normalize()
+		basis[(idx+2)%3] = basis[idx].Cross(basis[(idx+1)%3])
+		return basis
+
+	default:
+		return [3]glm.Vec3f{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	}
+}
+
+// SetParentKeepGlobalTransform makes entityId a child of parentId and updates its
+// Transform, so that the entity keeps its current pose in world space.
+// The pose is computed from the GlobalTransform values of the last transform propagation.
+func SetParentKeepGlobalTransform(commands *byke.Commands, entityId, parentId byke.EntityId) {
+	commands.RunSystemWith(setParentKeepGlobalTransformSystem, reparentCommand{
+		EntityId: entityId,
+		ParentId: parentId,
+	})
+}
+
+type reparentCommand struct {
+	EntityId byke.EntityId
+	ParentId byke.EntityId
+}
+
+func setParentKeepGlobalTransformSystem(
+	params byke.In[reparentCommand],
+	commands *byke.Commands,
+	query byke.Query[struct {
+		_               byke.Allow[byke.Disabled]
+		GlobalTransform GlobalTransform
+	}],
+) {
+	reparent := params.Value
+
+	entity, ok := query.Get(reparent.EntityId)
+	if !ok {
+		commands.Entity(reparent.EntityId).SetParent(reparent.ParentId)
+		return
+	}
+
+	parent, ok := query.Get(reparent.ParentId)
+	if !ok {
+		commands.Entity(reparent.EntityId).SetParent(reparent.ParentId)
+		return
+	}
+
+	parentInverse, ok := parent.GlobalTransform.Affine.TryInverse()
+	if !ok {
+		// parent is degenerated, e.g. has a scale of zero.
+		commands.Entity(reparent.EntityId).SetParent(reparent.ParentId)
+		return
+	}
+
+	local := TransformFromAffine(parentInverse.Mul(entity.GlobalTransform.Affine))
+
+	commands.Entity(reparent.EntityId).
+		Insert(local).
+		SetParent(reparent.ParentId)
+}
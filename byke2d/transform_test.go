@@ -3,6 +3,7 @@ package byke2d
 import (
 	"testing"
 
+	"github.com/chewxy/math32"
 	"github.com/oliverbestmann/byke/byke2d/glm"
 	"github.com/stretchr/testify/require"
 )
@@ -73,3 +74,37 @@ func equalVec(t *testing.T, exp, actual glm.Vec4f) {
 		require.InDelta(t, exp[i], actual[i], 1e-5)
 	}
 }
+
+func TestTransformFromAffine(t *testing.T) {
+	tr := Transform{
+		Translation: glm.Vec3f{1, 2, 3},
+		Scale:       glm.Vec3f{2, 3, 4},
+		Rotation:    glm.RotationZQuat(glm.DegToRad(30)),
+	}
+
+	decomposed := TransformFromAffine(tr.Affine3())
+
+	for _, point := range []glm.Vec4f{{0, 0, 0, 1}, {1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}} {
+		equalVec(t, tr.Affine3().Transform(point), decomposed.Affine3().Transform(point))
+	}
+}
+
+func TestTransformFromAffineZeroScale(t *testing.T) {
+	for _, scale := range []glm.Vec3f{{0, 3, 4}, {2, 0, 4}, {0, 0, 4}, {0, 0, 0}} {
+		tr := Transform{
+			Translation: glm.Vec3f{1, 2, 3},
+			Scale:       scale,
+			Rotation:    glm.RotationZQuat(glm.DegToRad(30)),
+		}
+
+		decomposed := TransformFromAffine(tr.Affine3())
+
+		for idx := range 4 {
+			require.False(t, math32.IsNaN(decomposed.Rotation.Values()[idx]))
+		}
+
+		for _, point := range []glm.Vec4f{{0, 0, 0, 1}, {1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}} {
+			equalVec(t, tr.Affine3().Transform(point), decomposed.Affine3().Transform(point))
+		}
+	}
+}
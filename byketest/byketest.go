@@ -0,0 +1,170 @@
+// Package byketest provides helpers to test systems and plugins.
+//
+// A test creates an App using New, configures it like a normal byke.App
+// and drives it frame by frame using App.Step:
+//
+//	app := byketest.New(t)
+//	app.AddSystems(byke.Update, moveSystem)
+//
+//	player := app.World().Spawn([]byke.ErasedComponent{Position{}, Velocity{X: 60}})
+//	app.Step(60)
+//
+//	require.InDelta(t, 60, app.ExpectComponent[Position](player).X, 1e-3)
+//
+// Time progresses using a byke.FakeClock, so every frame advances time by
+// exactly the same amount.
+package byketest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// DefaultStep is the amount of time each frame advances the clock.
+const DefaultStep = time.Second / 60
+
+// App wraps a byke.App for testing. All assertions fail the test
+// the App was created with.
+type App struct {
+	*byke.App
+
+	t testing.TB
+
+	// buffers of messages sent since the buffer was created
+	messages map[reflect.Type]messageBuffer
+}
+
+// New creates a new App for testing. The app uses a byke.FakeClock that
+// advances by DefaultStep each frame.
+func New(t testing.TB) *App {
+	app := &App{
+		App:      &byke.App{},
+		t:        t,
+		messages: map[reflect.Type]messageBuffer{},
+	}
+
+	app.InsertResource(byke.FakeClock{
+		Now:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Step: DefaultStep,
+	})
+
+	return app
+}
+
+// Step runs n frames of the app. The first frame also runs the startup schedules.
+// Plugins are finished before the first frame, see byke.App.FinishPlugins.
+func (a *App) Step(n int) {
+	a.t.Helper()
+
+	if err := a.FinishPlugins(); err != nil {
+		a.t.Fatal(err)
+	}
+
+	world := a.World()
+
+	for range n {
+		world.RunSchedule(byke.Main)
+
+		// collect messages before they are dropped by the message update system
+		for _, buffer := range a.messages {
+			buffer.collect()
+		}
+	}
+}
+
+// Clock returns the FakeClock of the app. Change its Step to
+// advance time by a different amount each frame.
+func (a *App) Clock() *byke.FakeClock {
+	return a.World().RequireResourceOf[byke.FakeClock]()
+}
+
+// RunSystem runs a single system outside of any schedule.
+func (a *App) RunSystem(system byke.AnySystem) {
+	a.World().RunSystem(system)
+}
+
+// ExpectEntity asserts that the entity exists.
+func (a *App) ExpectEntity(entityId byke.EntityId) byke.EntityRef {
+	a.t.Helper()
+
+	entity, ok := a.World().Entity(entityId)
+	if !ok {
+		a.t.Fatalf("expected entity %s to exist", entityId)
+	}
+
+	return entity
+}
+
+// ExpectNoEntity asserts that the entity does not exist.
+func (a *App) ExpectNoEntity(entityId byke.EntityId) {
+	a.t.Helper()
+
+	if _, ok := a.World().Entity(entityId); ok {
+		a.t.Fatalf("expected entity %s to not exist", entityId)
+	}
+}
+
+// ExpectComponent asserts that the entity has a component of type C and returns its value.
+func (a *App) ExpectComponent[C byke.IsComponent[C]](entityId byke.EntityId) C {
+	a.t.Helper()
+
+	entity := a.ExpectEntity(entityId)
+
+	var zeroValue C
+	componentType := zeroValue.ComponentType()
+
+	if !entity.Has(componentType) {
+		a.t.Fatalf("expected entity %s to have component %s", entityId, componentType)
+	}
+
+	return *any(entity.Get(componentType)).(*C)
+}
+
+// ExpectNoComponent asserts that the entity exists, but has no component of type C.
+func (a *App) ExpectNoComponent[C byke.IsComponent[C]](entityId byke.EntityId) {
+	a.t.Helper()
+
+	entity := a.ExpectEntity(entityId)
+
+	var zeroValue C
+	componentType := zeroValue.ComponentType()
+
+	if entity.Has(componentType) {
+		a.t.Fatalf("expected entity %s to not have component %s", entityId, componentType)
+	}
+}
+
+// ExpectResource asserts that a resource of type T exists and returns a pointer to it.
+func (a *App) ExpectResource[T any]() *T {
+	a.t.Helper()
+
+	res, ok := a.World().ResourceOf[T]()
+	if !ok {
+		a.t.Fatalf("expected resource %s to exist", reflect.TypeFor[T]())
+	}
+
+	return res
+}
+
+// ExpectState asserts the current value of the state S.
+func (a *App) ExpectState[S comparable](expected S) {
+	a.t.Helper()
+
+	state := a.ExpectResource[byke.State[S]]()
+	if current := state.Current(); current != expected {
+		a.t.Fatalf("expected state %v, got %v", expected, current)
+	}
+}
+
+// ExpectTransition asserts that the state S transitioned from one value to
+// another. See ExpectMessage on which transitions are observed.
+func (a *App) ExpectTransition[S comparable](from, to S) {
+	a.t.Helper()
+
+	a.ExpectMessage[byke.StateTransitionEvent[S]](func(event byke.StateTransitionEvent[S]) bool {
+		return event.PreviousState == from && event.CurrentState == to
+	})
+}
@@ -0,0 +1,85 @@
+package byketest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+type Position struct {
+	byke.ComparableComponent[Position]
+	X float64
+}
+
+type Velocity struct {
+	byke.ComparableComponent[Velocity]
+	X float64
+}
+
+type Bumped struct {
+	EntityId byke.EntityId
+}
+
+type GameState int
+
+const (
+	Playing GameState = iota
+	GameOver
+)
+
+type movingItem struct {
+	byke.EntityId
+	Position *Position
+	Velocity Velocity
+}
+
+func moveSystem(vt byke.VirtualTime, query byke.Query[movingItem], bumped *byke.MessageWriter[Bumped], nextState *byke.NextState[GameState]) {
+	for item := range query.Items() {
+		item.Position.X += item.Velocity.X * vt.Delta.Seconds()
+
+		if item.Position.X >= 100 {
+			bumped.Write(Bumped{EntityId: item.EntityId})
+			nextState.Set(GameOver)
+		}
+	}
+}
+
+func TestApp(t *testing.T) {
+	app := New(t)
+	app.AddMessage[Bumped]()
+	app.InitState(Playing)
+	app.AddSystems(byke.Update, moveSystem)
+
+	slow := app.World().Spawn([]byke.ErasedComponent{Position{}, Velocity{X: 30}})
+	fast := app.World().Spawn([]byke.ErasedComponent{Position{}, Velocity{X: 60}})
+
+	app.TrackMessages[Bumped]()
+
+	// the first frame has no delta
+	app.Step(1)
+	before := app.Snapshot[Position]()
+
+	app.Step(60)
+	require.InDelta(t, 60, app.ExpectComponent[Position](fast).X, 1e-3)
+	app.ExpectNoComponent[Velocity](app.World().Spawn(nil))
+	app.ExpectNoMessage[Bumped]()
+	app.ExpectState(Playing)
+
+	diff := Diff(before, app.Snapshot[Position]())
+	require.Len(t, diff.Changed, 2)
+	require.InDelta(t, 30, diff.Changed[slow].After.X, 1e-3)
+
+	app.Step(42)
+	app.ExpectMessage(func(msg Bumped) bool { return msg.EntityId == fast })
+	app.ExpectTransition(Playing, GameOver)
+	app.ExpectState(GameOver)
+
+	app.World().Despawn(slow)
+	app.ExpectNoEntity(slow)
+
+	// the clock advances exactly one step per frame, the first frame has no delta
+	require.Equal(t, 102*DefaultStep, app.ExpectResource[byke.VirtualTime]().Elapsed)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(103*DefaultStep), app.Clock().Now)
+}
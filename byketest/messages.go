@@ -0,0 +1,102 @@
+package byketest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/oliverbestmann/byke"
+)
+
+type messageBuffer interface {
+	collect()
+}
+
+type typedMessageBuffer[E any] struct {
+	reader   *byke.MessageReader[E]
+	messages []E
+}
+
+func (b *typedMessageBuffer[E]) collect() {
+	b.messages = append(b.messages, b.reader.Read()...)
+}
+
+// messagesOf returns the buffer for messages of type E. The buffer is created
+// on first use and initially holds all messages still retained by the world.
+func (a *App) messagesOf[E any]() *typedMessageBuffer[E] {
+	a.t.Helper()
+
+	messageType := reflect.TypeFor[E]()
+
+	buffer, ok := a.messages[messageType]
+	if !ok {
+		messages, ok := a.World().ResourceOf[byke.Messages[E]]()
+		if !ok {
+			a.t.Fatalf("message type %s not registered", messageType)
+		}
+
+		buffer = &typedMessageBuffer[E]{reader: messages.Reader()}
+		a.messages[messageType] = buffer
+	}
+
+	typed := buffer.(*typedMessageBuffer[E])
+	typed.collect()
+
+	return typed
+}
+
+// TrackMessages starts recording messages of type E. Messages are only retained
+// by the world for two frames. Call TrackMessages before running more frames to
+// not miss any messages in a later ExpectMessage.
+func (a *App) TrackMessages[E any]() {
+	a.t.Helper()
+	a.messagesOf[E]()
+}
+
+// ExpectMessage asserts that a message of type E was sent and returns it. If match functions
+// are given, the message must satisfy all of them. The message is consumed, a second call
+// expects another message.
+func (a *App) ExpectMessage[E any](match ...func(E) bool) E {
+	a.t.Helper()
+
+	buffer := a.messagesOf[E]()
+
+	for idx, message := range buffer.messages {
+		if matchesAll(message, match) {
+			buffer.messages = append(buffer.messages[:idx], buffer.messages[idx+1:]...)
+			return message
+		}
+	}
+
+	a.t.Fatalf("expected a matching message of type %s, got %s", reflect.TypeFor[E](), formatMessages(buffer.messages))
+
+	var zeroValue E
+	return zeroValue
+}
+
+// ExpectNoMessage asserts that no message of type E was sent that was not yet consumed by ExpectMessage.
+func (a *App) ExpectNoMessage[E any]() {
+	a.t.Helper()
+
+	buffer := a.messagesOf[E]()
+	if len(buffer.messages) > 0 {
+		a.t.Fatalf("expected no message of type %s, got %s", reflect.TypeFor[E](), formatMessages(buffer.messages))
+	}
+}
+
+func matchesAll[E any](message E, match []func(E) bool) bool {
+	for _, fn := range match {
+		if !fn(message) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatMessages[E any](messages []E) string {
+	if len(messages) == 0 {
+		return "none"
+	}
+
+	return fmt.Sprintf("%+v", messages)
+}
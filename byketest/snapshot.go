@@ -0,0 +1,102 @@
+package byketest
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// Snapshot holds a copy of all component values of type C, keyed by entity.
+type Snapshot[C byke.IsComponent[C]] map[byke.EntityId]C
+
+// Snapshot takes a snapshot of all components of type C. Compare two
+// snapshots taken in different frames using Diff.
+func (a *App) Snapshot[C byke.IsComponent[C]]() Snapshot[C] {
+	type snapshotItem struct {
+		byke.EntityId
+		Value C
+	}
+
+	snapshot := Snapshot[C]{}
+
+	query := a.World().Query[snapshotItem]()
+	for item := range query.Items() {
+		snapshot[item.EntityId] = item.Value
+	}
+
+	return snapshot
+}
+
+// Change describes a component value that changed between two snapshots.
+type Change[C any] struct {
+	Before C
+	After  C
+}
+
+// SnapshotDiff is the difference between two snapshots.
+type SnapshotDiff[C byke.IsComponent[C]] struct {
+	Added   map[byke.EntityId]C
+	Removed map[byke.EntityId]C
+	Changed map[byke.EntityId]Change[C]
+}
+
+// Diff computes the difference between two snapshots of the same component type.
+// Values are compared using reflect.DeepEqual.
+func Diff[C byke.IsComponent[C]](before, after Snapshot[C]) SnapshotDiff[C] {
+	diff := SnapshotDiff[C]{
+		Added:   map[byke.EntityId]C{},
+		Removed: map[byke.EntityId]C{},
+		Changed: map[byke.EntityId]Change[C]{},
+	}
+
+	for entityId, valueBefore := range before {
+		valueAfter, ok := after[entityId]
+		switch {
+		case !ok:
+			diff.Removed[entityId] = valueBefore
+
+		case !reflect.DeepEqual(valueBefore, valueAfter):
+			diff.Changed[entityId] = Change[C]{Before: valueBefore, After: valueAfter}
+		}
+	}
+
+	for entityId, valueAfter := range after {
+		if _, ok := before[entityId]; !ok {
+			diff.Added[entityId] = valueAfter
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns true, if both snapshots were equal.
+func (d SnapshotDiff[C]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d SnapshotDiff[C]) String() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+
+	var sb strings.Builder
+
+	for _, entityId := range slices.Sorted(maps.Keys(d.Added)) {
+		_, _ = fmt.Fprintf(&sb, "+ %s: %+v\n", entityId, d.Added[entityId])
+	}
+
+	for _, entityId := range slices.Sorted(maps.Keys(d.Removed)) {
+		_, _ = fmt.Fprintf(&sb, "- %s: %+v\n", entityId, d.Removed[entityId])
+	}
+
+	for _, entityId := range slices.Sorted(maps.Keys(d.Changed)) {
+		change := d.Changed[entityId]
+		_, _ = fmt.Fprintf(&sb, "~ %s: %+v -> %+v\n", entityId, change.Before, change.After)
+	}
+
+	return sb.String()
+}
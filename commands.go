@@ -2,6 +2,7 @@ package byke
 
 import (
 	"fmt"
+	"log/slog"
 	"reflect"
 
 	"github.com/oliverbestmann/byke/spoke"
@@ -73,6 +74,36 @@ func (c *Commands) Spawn(components ...ErasedComponent) EntityCommands {
 	}
 }
 
+// SpawnBatch spawns n entities. The components of the i-th entity are
+// provided by calling makeComponents(i). makeComponents is called directly,
+// while the entities are spawned when the commands are applied.
+// See World.SpawnBatch.
+func (c *Commands) SpawnBatch(n int, makeComponents func(i int) []ErasedComponent) []EntityId {
+	entityIds := make([]EntityId, n)
+	components := make([][]ErasedComponent, n)
+
+	for idx := range n {
+		entityIds[idx] = c.world.reserveEntityId()
+		components[idx] = makeComponents(idx)
+	}
+
+	c.Add(&spawnBatchCommand{
+		EntityIds:  entityIds,
+		Components: components,
+	})
+
+	return entityIds
+}
+
+// InsertBatch inserts the same component into all the given entities.
+// See World.InsertBatch.
+func (c *Commands) InsertBatch(entityIds []EntityId, component ErasedComponent) *Commands {
+	return c.Add(&insertBatchCommand{
+		EntityIds: entityIds,
+		Component: component,
+	})
+}
+
 func (c *Commands) RunSystem(system AnySystem) *Commands {
 	return c.Add(&runSystemCommand{
 		System: system,
@@ -109,7 +140,28 @@ func (e EntityCommands) Id() EntityId {
 }
 
 func (e EntityCommands) Despawn() {
-	e.commands.queue = append(e.commands.queue, &despawnCommand{e.entityId})
+	e.commands.queue = append(e.commands.queue, &despawnCommand{EntityId: e.entityId})
+}
+
+// TryDespawn despawns the entity like Despawn, but does not log a warning
+// if the entity does not exist. Commands can not return errors, so
+// the failure is only logged at debug level. Use World.TryDespawn in an
+// exclusive system to handle the error yourself.
+func (e EntityCommands) TryDespawn() {
+	e.commands.Add(&despawnCommand{EntityId: e.entityId, Mode: despawnTry})
+}
+
+// DespawnOnly despawns the entity, but not its children.
+// See World.DespawnOnly.
+func (e EntityCommands) DespawnOnly() {
+	e.commands.Add(&despawnCommand{EntityId: e.entityId, Mode: despawnOnly})
+}
+
+// DespawnDescendants despawns all children of this entity, but keeps the entity itself.
+// See World.DespawnDescendants.
+func (e EntityCommands) DespawnDescendants() EntityCommands {
+	e.commands.Add(&despawnCommand{EntityId: e.entityId, Mode: despawnDescendants})
+	return e
 }
 
 // Trigger triggers the given EntityEvent.
@@ -176,6 +228,24 @@ func (c *spawnCommand) Apply(world *World) {
 	world.spawnWithEntityId(c.EntityId, c.Components)
 }
 
+type spawnBatchCommand struct {
+	EntityIds  []EntityId
+	Components [][]ErasedComponent
+}
+
+func (c *spawnBatchCommand) Apply(world *World) {
+	world.spawnBatchWithEntityIds(c.EntityIds, c.Components)
+}
+
+type insertBatchCommand struct {
+	EntityIds []EntityId
+	Component ErasedComponent
+}
+
+func (c *insertBatchCommand) Apply(world *World) {
+	world.InsertBatch(c.EntityIds, c.Component)
+}
+
 type applyEntityCommands struct {
 	EntityId EntityId
 	Commands []EntityCommand
@@ -187,12 +257,33 @@ func (c *applyEntityCommands) Apply(world *World) {
 	}
 }
 
+type despawnMode uint8
+
+const (
+	despawnRecursive despawnMode = iota
+	despawnTry
+	despawnOnly
+	despawnDescendants
+)
+
 type despawnCommand struct {
 	EntityId EntityId
+	Mode     despawnMode
 }
 
 func (c *despawnCommand) Apply(world *World) {
-	world.Despawn(c.EntityId)
+	switch c.Mode {
+	case despawnRecursive:
+		world.Despawn(c.EntityId)
+	case despawnTry:
+		if err := world.TryDespawn(c.EntityId); err != nil {
+			slog.Debug("Skipping despawn of entity", slog.String("err", err.Error()))
+		}
+	case despawnOnly:
+		world.DespawnOnly(c.EntityId)
+	case despawnDescendants:
+		world.DespawnDescendants(c.EntityId)
+	}
 }
 
 type observeCommand struct {
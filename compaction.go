@@ -0,0 +1,59 @@
+package byke
+
+import (
+	"log/slog"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// CompactionStats reports what was reclaimed by an archetype compaction.
+type CompactionStats = spoke.CompactionStats
+
+// ArchetypeCompaction configures the periodic reclamation of empty archetypes.
+// Insert this resource into the world to enable compaction in the Last schedule.
+type ArchetypeCompaction struct {
+	// Interval is the number of frames between two checks.
+	// A value of zero checks every frame.
+	Interval int
+
+	// MinEmptyArchetypes is the number of empty archetypes required
+	// to run a compaction.
+	MinEmptyArchetypes int
+
+	// Runs counts the number of compactions executed so far.
+	Runs int
+
+	// Reclaimed holds the sum of everything reclaimed so far.
+	Reclaimed CompactionStats
+}
+
+// CompactArchetypes removes all empty archetypes from the world and
+// updates all cached queries.
+func (w *World) CompactArchetypes() CompactionStats {
+	return w.storage.Compact()
+}
+
+func compactArchetypesSystem(world *World, compaction *ArchetypeCompaction, frame *Local[int]) {
+	frame.Value += 1
+	if frame.Value <= compaction.Interval {
+		return
+	}
+
+	frame.Value = 0
+
+	if world.storage.EmptyArchetypeCount() < max(1, compaction.MinEmptyArchetypes) {
+		return
+	}
+
+	stats := world.CompactArchetypes()
+
+	compaction.Runs += 1
+	compaction.Reclaimed = compaction.Reclaimed.Add(stats)
+
+	slog.Debug(
+		"Compacted archetypes",
+		slog.Int("archetypes", stats.Archetypes),
+		slog.Int("transitions", stats.Transitions),
+		slog.Int("queryAccessors", stats.QueryAccessors),
+	)
+}
@@ -0,0 +1,121 @@
+package byke
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+func (d *Diagnostic) String() string {
+	latest, _ := d.Latest()
+
+	return fmt.Sprintf(
+		"%s: %.2f%s (avg %.2f%s, p95 %.2f%s)",
+		d.Path,
+		latest, d.Suffix,
+		d.Average(), d.Suffix,
+		d.Percentile(95), d.Suffix,
+	)
+}
+
+// LogDiagnosticsSink logs the latest value, average and percentiles of each Diagnostic.
+type LogDiagnosticsSink struct {
+	// Level to log the diagnostics at. Defaults to slog.LevelInfo.
+	Level slog.Level
+}
+
+func (s LogDiagnosticsSink) Report(diagnostics *Diagnostics) {
+	for _, diagnostic := range diagnostics.All() {
+		latest, ok := diagnostic.Latest()
+		if !ok {
+			continue
+		}
+
+		slog.Log(context.Background(), s.Level, "Diagnostic",
+			slog.String("path", string(diagnostic.Path)),
+			slog.Float64("latest", latest),
+			slog.Float64("avg", diagnostic.Average()),
+			slog.Float64("p50", diagnostic.Percentile(50)),
+			slog.Float64("p95", diagnostic.Percentile(95)),
+			slog.Float64("p99", diagnostic.Percentile(99)),
+		)
+	}
+}
+
+// CSVDiagnosticsSink writes one row per Diagnostic and report. The columns are
+// time, path, latest, avg, p50, p95 and p99.
+type CSVDiagnosticsSink struct {
+	writer *csv.Writer
+	closer io.Closer
+}
+
+// NewCSVDiagnosticsSink creates a sink that writes into a new file at the given path.
+func NewCSVDiagnosticsSink(path string) (*CSVDiagnosticsSink, error) {
+	fp, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create diagnostics file: %w", err)
+	}
+
+	return NewCSVDiagnosticsSinkWriter(fp), nil
+}
+
+// NewCSVDiagnosticsSinkWriter creates a sink that writes to w. If w implements
+// io.Closer, it is closed by CSVDiagnosticsSink.Close.
+func NewCSVDiagnosticsSinkWriter(w io.Writer) *CSVDiagnosticsSink {
+	sink := &CSVDiagnosticsSink{writer: csv.NewWriter(w)}
+
+	if closer, ok := w.(io.Closer); ok {
+		sink.closer = closer
+	}
+
+	_ = sink.writer.Write([]string{"time", "path", "latest", "avg", "p50", "p95", "p99"})
+
+	return sink
+}
+
+func (s *CSVDiagnosticsSink) Report(diagnostics *Diagnostics) {
+	now := time.Now().Format(time.RFC3339Nano)
+
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	for _, diagnostic := range diagnostics.All() {
+		latest, ok := diagnostic.Latest()
+		if !ok {
+			continue
+		}
+
+		_ = s.writer.Write([]string{
+			now,
+			string(diagnostic.Path),
+			formatFloat(latest),
+			formatFloat(diagnostic.Average()),
+			formatFloat(diagnostic.Percentile(50)),
+			formatFloat(diagnostic.Percentile(95)),
+			formatFloat(diagnostic.Percentile(99)),
+		})
+	}
+
+	s.writer.Flush()
+
+	if err := s.writer.Error(); err != nil {
+		slog.Warn("Failed to write diagnostics", slog.String("err", err.Error()))
+	}
+}
+
+// Close flushes pending rows and closes the underlying writer.
+func (s *CSVDiagnosticsSink) Close() error {
+	s.writer.Flush()
+
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+
+	return s.writer.Error()
+}
@@ -0,0 +1,260 @@
+package byke
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// DiagnosticPath identifies a Diagnostic, e.g. "frame_time".
+type DiagnosticPath string
+
+// Diagnostics measured by PluginDiagnostics.
+const (
+	DiagnosticFrameTime      DiagnosticPath = "frame_time"
+	DiagnosticFPS            DiagnosticPath = "fps"
+	DiagnosticEntityCount    DiagnosticPath = "entity_count"
+	DiagnosticArchetypeCount DiagnosticPath = "archetype_count"
+	DiagnosticCommandCount   DiagnosticPath = "command_count"
+)
+
+// Diagnostic is a named measurement that keeps a history of its most recent values.
+type Diagnostic struct {
+	Path DiagnosticPath
+
+	// Suffix is appended to the value when formatted, e.g. "ms"
+	Suffix string
+
+	// ring buffer of recent values
+	history []float64
+	next    int
+	len     int
+}
+
+// Add adds a new value to the history. If the history is full,
+// the oldest value is dropped.
+func (d *Diagnostic) Add(value float64) {
+	if len(d.history) == 0 {
+		return
+	}
+
+	d.history[d.next] = value
+	d.next = (d.next + 1) % len(d.history)
+	d.len = min(d.len+1, len(d.history))
+}
+
+// Len returns the number of values in the history.
+func (d *Diagnostic) Len() int {
+	return d.len
+}
+
+// Latest returns the most recently added value.
+func (d *Diagnostic) Latest() (float64, bool) {
+	if d.len == 0 {
+		return 0, false
+	}
+
+	idx := (d.next - 1 + len(d.history)) % len(d.history)
+	return d.history[idx], true
+}
+
+// Values returns the history of values, oldest first.
+func (d *Diagnostic) Values() []float64 {
+	values := make([]float64, 0, d.len)
+
+	start := (d.next - d.len + len(d.history)) % max(1, len(d.history))
+	for idx := range d.len {
+		values = append(values, d.history[(start+idx)%len(d.history)])
+	}
+
+	return values
+}
+
+// Average returns the mean of all values in the history.
+func (d *Diagnostic) Average() float64 {
+	if d.len == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, value := range d.Values() {
+		sum += value
+	}
+
+	return sum / float64(d.len)
+}
+
+// Percentile returns the p-th percentile of the values in the history,
+// with p in range 0 to 100. Values between two samples are interpolated linearly.
+func (d *Diagnostic) Percentile(p float64) float64 {
+	if d.len == 0 {
+		return 0
+	}
+
+	values := d.Values()
+	slices.Sort(values)
+
+	rank := p / 100 * float64(len(values)-1)
+	rank = min(max(rank, 0), float64(len(values)-1))
+
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	frac := rank - float64(lower)
+	return values[lower]*(1-frac) + values[upper]*frac
+}
+
+// DiagnosticsSink receives the Diagnostics in the interval configured
+// in DiagnosticsConfig.ReportInterval.
+type DiagnosticsSink interface {
+	Report(diagnostics *Diagnostics)
+}
+
+// Diagnostics is a registry of Diagnostic values. It is available as a
+// resource after adding PluginDiagnostics.
+type Diagnostics struct {
+	historyLength int
+
+	byPath map[DiagnosticPath]*Diagnostic
+	order  []*Diagnostic
+
+	sinks []DiagnosticsSink
+}
+
+// NewDiagnostics creates a new registry keeping historyLength values per Diagnostic.
+func NewDiagnostics(historyLength int) Diagnostics {
+	return Diagnostics{
+		historyLength: max(1, historyLength),
+		byPath:        map[DiagnosticPath]*Diagnostic{},
+	}
+}
+
+// Register registers a new Diagnostic. If a Diagnostic with the same path
+// already exists, it is returned instead.
+func (d *Diagnostics) Register(path DiagnosticPath, suffix string) *Diagnostic {
+	if existing, ok := d.byPath[path]; ok {
+		return existing
+	}
+
+	diagnostic := &Diagnostic{
+		Path:    path,
+		Suffix:  suffix,
+		history: make([]float64, d.historyLength),
+	}
+
+	d.byPath[path] = diagnostic
+	d.order = append(d.order, diagnostic)
+
+	return diagnostic
+}
+
+// Add adds a value to the Diagnostic with the given path. The Diagnostic
+// is registered without a suffix if it does not yet exist.
+func (d *Diagnostics) Add(path DiagnosticPath, value float64) {
+	d.Register(path, "").Add(value)
+}
+
+// Get returns the Diagnostic with the given path.
+func (d *Diagnostics) Get(path DiagnosticPath) (*Diagnostic, bool) {
+	diagnostic, ok := d.byPath[path]
+	return diagnostic, ok
+}
+
+// All returns all Diagnostic values in order of registration.
+func (d *Diagnostics) All() []*Diagnostic {
+	return d.order
+}
+
+// AddSink adds a sink that receives the Diagnostics periodically.
+func (d *Diagnostics) AddSink(sink DiagnosticsSink) {
+	d.sinks = append(d.sinks, sink)
+}
+
+// DiagnosticsConfig configures PluginDiagnostics.
+type DiagnosticsConfig struct {
+	// HistoryLength is the number of values kept per Diagnostic. Defaults to 120.
+	HistoryLength int
+
+	// ReportInterval is the time between two reports to the sinks. Defaults to one second.
+	ReportInterval time.Duration
+
+	// Sinks receive the diagnostics every ReportInterval. More sinks
+	// can be added later using Diagnostics.AddSink.
+	Sinks []DiagnosticsSink
+}
+
+type diagnosticsReportInterval time.Duration
+
+// PluginDiagnostics measures frame time, fps, entity count, archetype count and
+// number of applied commands per frame. Other plugins can add their own measurements
+// to the Diagnostics resource.
+func PluginDiagnostics(config DiagnosticsConfig) Plugin {
+	if config.HistoryLength <= 0 {
+		config.HistoryLength = 120
+	}
+
+	if config.ReportInterval <= 0 {
+		config.ReportInterval = time.Second
+	}
+
+	return func(app *App) {
+		diagnostics := NewDiagnostics(config.HistoryLength)
+		diagnostics.Register(DiagnosticFrameTime, "ms")
+		diagnostics.Register(DiagnosticFPS, "")
+		diagnostics.Register(DiagnosticEntityCount, "")
+		diagnostics.Register(DiagnosticArchetypeCount, "")
+		diagnostics.Register(DiagnosticCommandCount, "")
+
+		for _, sink := range config.Sinks {
+			diagnostics.AddSink(sink)
+		}
+
+		app.InsertResource(diagnostics)
+		app.InsertResource(diagnosticsReportInterval(config.ReportInterval))
+
+		app.AddSystems(Last, System(measureDiagnosticsSystem, reportDiagnosticsSystem).Chain())
+	}
+}
+
+func measureDiagnosticsSystem(
+	world *World,
+	diagnostics *Diagnostics,
+	lastFrame *Local[time.Time],
+	lastAppliedCommands *Local[uint64],
+) {
+	now := time.Now()
+
+	if !lastFrame.Value.IsZero() {
+		frameTime := now.Sub(lastFrame.Value)
+		diagnostics.Add(DiagnosticFrameTime, float64(frameTime)/float64(time.Millisecond))
+
+		if frameTime > 0 {
+			diagnostics.Add(DiagnosticFPS, 1/frameTime.Seconds())
+		}
+	}
+
+	lastFrame.Value = now
+
+	diagnostics.Add(DiagnosticEntityCount, float64(world.storage.EntityCount()))
+	diagnostics.Add(DiagnosticArchetypeCount, float64(world.storage.ArchetypeCount()))
+
+	diagnostics.Add(DiagnosticCommandCount, float64(world.appliedCommands-lastAppliedCommands.Value))
+	lastAppliedCommands.Value = world.appliedCommands
+}
+
+func reportDiagnosticsSystem(
+	diagnostics *Diagnostics,
+	interval diagnosticsReportInterval,
+	lastReport *Local[time.Time],
+) {
+	now := time.Now()
+	if now.Sub(lastReport.Value) < time.Duration(interval) {
+		return
+	}
+
+	lastReport.Value = now
+
+	for _, sink := range diagnostics.sinks {
+		sink.Report(diagnostics)
+	}
+}
@@ -0,0 +1,75 @@
+package byke
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostic(t *testing.T) {
+	diagnostics := NewDiagnostics(4)
+	diagnostic := diagnostics.Register("value", "ms")
+
+	_, ok := diagnostic.Latest()
+	require.False(t, ok)
+
+	for _, value := range []float64{1, 2, 3, 4, 5, 6} {
+		diagnostic.Add(value)
+	}
+
+	// only the last four values are kept
+	require.Equal(t, []float64{3, 4, 5, 6}, diagnostic.Values())
+	require.Equal(t, 4.5, diagnostic.Average())
+	require.Equal(t, 3.0, diagnostic.Percentile(0))
+	require.Equal(t, 4.5, diagnostic.Percentile(50))
+	require.Equal(t, 6.0, diagnostic.Percentile(100))
+
+	latest, _ := diagnostic.Latest()
+	require.Equal(t, 6.0, latest)
+}
+
+type recordingSink struct {
+	reports int
+}
+
+func (s *recordingSink) Report(*Diagnostics) {
+	s.reports += 1
+}
+
+func TestPluginDiagnostics(t *testing.T) {
+	var output bytes.Buffer
+	csvSink := NewCSVDiagnosticsSinkWriter(&output)
+
+	sink := &recordingSink{}
+
+	var app App
+	app.AddPlugin(PluginDiagnostics(DiagnosticsConfig{Sinks: []DiagnosticsSink{sink, csvSink}}))
+
+	app.AddSystems(Update, func(commands *Commands) {
+		commands.Spawn(Named("Test"))
+	})
+
+	w := app.World()
+	w.RunSchedule(Main)
+	w.RunSchedule(Main)
+
+	diagnostics := w.RequireResourceOf[Diagnostics]()
+
+	entityCount, _ := diagnostics.Get(DiagnosticEntityCount)
+	require.Equal(t, []float64{1, 2}, entityCount.Values())
+
+	commandCount, _ := diagnostics.Get(DiagnosticCommandCount)
+	latest, _ := commandCount.Latest()
+	require.Equal(t, 1.0, latest)
+
+	frameTime, _ := diagnostics.Get(DiagnosticFrameTime)
+	require.Equal(t, 1, frameTime.Len())
+
+	// the first frame always reports
+	require.Equal(t, 1, sink.reports)
+	require.NoError(t, csvSink.Close())
+	require.True(t, strings.HasPrefix(output.String(), "time,path,latest,avg,p50,p95,p99\n"))
+	require.Contains(t, output.String(), ",entity_count,1,1,1,1,1\n")
+}
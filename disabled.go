@@ -0,0 +1,60 @@
+package byke
+
+import "github.com/oliverbestmann/byke/spoke"
+
+// DisableHierarchy inserts the Disabled component into the given
+// entity and all of its descendants.
+func (w *World) DisableHierarchy(entityId EntityId) {
+	for _, entityId := range w.hierarchyOf(entityId) {
+		w.insertComponents(entityId, []ErasedComponent{Disabled{}})
+	}
+}
+
+// EnableHierarchy removes the Disabled component from the given
+// entity and all of its descendants.
+func (w *World) EnableHierarchy(entityId EntityId) {
+	disabled := spoke.ComponentTypeOf[Disabled]()
+
+	for _, entityId := range w.hierarchyOf(entityId) {
+		w.removeComponent(entityId, disabled)
+	}
+}
+
+// hierarchyOf returns the given entity followed by all of its
+// descendants, in breadth first order.
+func (w *World) hierarchyOf(entityId EntityId) []EntityId {
+	entityIds := []EntityId{entityId}
+
+	for descendantId := range w.Hierarchy().DescendantsBreadthFirst(entityId) {
+		entityIds = append(entityIds, descendantId)
+	}
+
+	return entityIds
+}
+
+// DisableHierarchy disables this entity and all of its descendants.
+// See World.DisableHierarchy.
+func (e EntityCommands) DisableHierarchy() EntityCommands {
+	e.commands.Add(&disableHierarchyCommand{EntityId: e.entityId})
+	return e
+}
+
+// EnableHierarchy enables this entity and all of its descendants.
+// See World.EnableHierarchy.
+func (e EntityCommands) EnableHierarchy() EntityCommands {
+	e.commands.Add(&disableHierarchyCommand{EntityId: e.entityId, Enable: true})
+	return e
+}
+
+type disableHierarchyCommand struct {
+	EntityId EntityId
+	Enable   bool
+}
+
+func (c *disableHierarchyCommand) Apply(world *World) {
+	if c.Enable {
+		world.EnableHierarchy(c.EntityId)
+	} else {
+		world.DisableHierarchy(c.EntityId)
+	}
+}
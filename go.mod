@@ -3,6 +3,7 @@ module github.com/oliverbestmann/byke
 go 1.27rc2
 
 require (
+	github.com/dop251/goja v0.0.0-20260806115107-493f22071ef6
 	github.com/ebitengine/oto/v3 v3.4.0
 	github.com/go-gl/glfw/v3.4/glfw v0.1.0-pre.1.0.20260628091122-0bd588dc30cf
 	github.com/go-text/render v0.2.1
@@ -24,7 +25,6 @@ require (
 	github.com/chewxy/math32 v1.11.2 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/dlclark/regexp2/v2 v2.6.0 // indirect
-	github.com/dop251/goja v0.0.0-20260806115107-493f22071ef6 // indirect
 	github.com/ebitengine/purego v0.10.0 // indirect
 	github.com/felixge/fgprof v0.9.5 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.4+incompatible // indirect
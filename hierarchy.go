@@ -0,0 +1,308 @@
+package byke
+
+import (
+	"fmt"
+	"iter"
+	"log/slog"
+	"reflect"
+	"slices"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// Hierarchy is a SystemParam to navigate the ChildOf/Children hierarchy.
+// Unlike a Query, it also visits entities that are Disabled.
+type Hierarchy struct {
+	world *World
+}
+
+// Hierarchy returns a Hierarchy to navigate the entities of this world.
+func (w *World) Hierarchy() Hierarchy {
+	return Hierarchy{world: w}
+}
+
+func (Hierarchy) newState(world *World, _ hierarchyT) SystemParamState {
+	return valueSystemParamState(reflect.ValueOf(world.Hierarchy()))
+}
+
+type hierarchyT interface {
+	newState(*World, hierarchyT) SystemParamState
+}
+
+// Parent returns the parent of the given entity, if it has one.
+func (h Hierarchy) Parent(entityId EntityId) (EntityId, bool) {
+	entity, ok := h.world.storage.Get(entityId)
+	if !ok {
+		return NoEntityId, false
+	}
+
+	childOf, ok := entity.Get(spoke.ComponentTypeOf[ChildOf]()).(*ChildOf)
+	if !ok {
+		return NoEntityId, false
+	}
+
+	return childOf.Parent, true
+}
+
+// Children returns the direct children of the given entity.
+// You **must not** modify the returned slice.
+func (h Hierarchy) Children(entityId EntityId) []EntityId {
+	entity, ok := h.world.storage.Get(entityId)
+	if !ok {
+		return nil
+	}
+
+	children, ok := entity.Get(spoke.ComponentTypeOf[Children]()).(*Children)
+	if !ok {
+		return nil
+	}
+
+	return children.Children()
+}
+
+// Ancestors yields the parent of the given entity, then its grandparent,
+// and so on, up to the root of the hierarchy.
+func (h Hierarchy) Ancestors(entityId EntityId) iter.Seq[EntityId] {
+	return func(yield func(EntityId) bool) {
+		for {
+			parentId, ok := h.Parent(entityId)
+			if !ok || !yield(parentId) {
+				return
+			}
+
+			entityId = parentId
+		}
+	}
+}
+
+// Root returns the root of the hierarchy the given entity belongs to.
+// If the entity has no parent, the entity itself is returned.
+func (h Hierarchy) Root(entityId EntityId) EntityId {
+	for ancestorId := range h.Ancestors(entityId) {
+		entityId = ancestorId
+	}
+
+	return entityId
+}
+
+// Siblings yields the other children of the parent of the given entity.
+func (h Hierarchy) Siblings(entityId EntityId) iter.Seq[EntityId] {
+	return func(yield func(EntityId) bool) {
+		parentId, ok := h.Parent(entityId)
+		if !ok {
+			return
+		}
+
+		for _, siblingId := range h.Children(parentId) {
+			if siblingId != entityId && !yield(siblingId) {
+				return
+			}
+		}
+	}
+}
+
+// Descendants yields all descendants of the given entity in depth first order.
+// A parent is always yielded before its children.
+func (h Hierarchy) Descendants(entityId EntityId) iter.Seq[EntityId] {
+	return func(yield func(EntityId) bool) {
+		stack := slices.Clone(h.Children(entityId))
+		slices.Reverse(stack)
+
+		for len(stack) > 0 {
+			entityId := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if !yield(entityId) {
+				return
+			}
+
+			// push in reverse, so the first child is visited next
+			children := h.Children(entityId)
+			for idx := len(children) - 1; idx >= 0; idx-- {
+				stack = append(stack, children[idx])
+			}
+		}
+	}
+}
+
+// DescendantsBreadthFirst yields all descendants of the given entity in breadth first order.
+func (h Hierarchy) DescendantsBreadthFirst(entityId EntityId) iter.Seq[EntityId] {
+	return func(yield func(EntityId) bool) {
+		queue := slices.Clone(h.Children(entityId))
+
+		for idx := 0; idx < len(queue); idx++ {
+			if !yield(queue[idx]) {
+				return
+			}
+
+			queue = append(queue, h.Children(queue[idx])...)
+		}
+	}
+}
+
+// IsAncestorOf returns true, if ancestorId is a (transitive) parent of entityId.
+func (h Hierarchy) IsAncestorOf(ancestorId, entityId EntityId) bool {
+	for parentId := range h.Ancestors(entityId) {
+		if parentId == ancestorId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetParent makes the given entity a child of parentId. If the entity
+// already has a parent, it is removed from the children of that parent.
+// Panics, if this would create a cycle in the hierarchy.
+func (w *World) SetParent(entityId, parentId EntityId) {
+	if err := w.trySetParent(entityId, parentId); err != nil {
+		panic(err)
+	}
+}
+
+func (w *World) trySetParent(entityId, parentId EntityId) error {
+	if err := w.checkParentCycle(entityId, parentId); err != nil {
+		return err
+	}
+
+	w.insertComponents(entityId, []ErasedComponent{ChildOf{Parent: parentId}})
+	return nil
+}
+
+// checkParentCycle returns an error, if making entityId a child
+// of parentId would create a cycle in the hierarchy.
+func (w *World) checkParentCycle(entityId, parentId EntityId) error {
+	if entityId == parentId || w.Hierarchy().IsAncestorOf(entityId, parentId) {
+		return fmt.Errorf("can not make %s a child of %s: would create a cycle", entityId, parentId)
+	}
+
+	return nil
+}
+
+// RemoveParent removes the given entity from its parent, making it a root entity.
+func (w *World) RemoveParent(entityId EntityId) {
+	w.removeComponent(entityId, spoke.ComponentTypeOf[ChildOf]())
+}
+
+// InsertChildAt makes childId a child of parentId and moves it to the given
+// index within the parents Children. Panics, if this would create a cycle in the hierarchy.
+func (w *World) InsertChildAt(parentId EntityId, index int, childId EntityId) {
+	if err := w.tryInsertChildAt(parentId, index, childId); err != nil {
+		panic(err)
+	}
+}
+
+func (w *World) tryInsertChildAt(parentId EntityId, index int, childId EntityId) error {
+	if err := w.trySetParent(childId, parentId); err != nil {
+		return err
+	}
+
+	w.updateChildren(parentId, func(children *Children) {
+		children.moveChild(childId, index)
+	})
+
+	return nil
+}
+
+// ReplaceChildren replaces all children of parentId with the given children, keeping
+// their order. Previous children that are not part of the new list become root entities.
+// Panics, if this would create a cycle in the hierarchy.
+func (w *World) ReplaceChildren(parentId EntityId, children []EntityId) {
+	if err := w.tryReplaceChildren(parentId, children); err != nil {
+		panic(err)
+	}
+}
+
+func (w *World) tryReplaceChildren(parentId EntityId, children []EntityId) error {
+	// check all children first, so we do not apply the change partially
+	for _, childId := range children {
+		if err := w.checkParentCycle(childId, parentId); err != nil {
+			return err
+		}
+	}
+
+	for _, childId := range slices.Clone(w.Hierarchy().Children(parentId)) {
+		if !slices.Contains(children, childId) {
+			w.RemoveParent(childId)
+		}
+	}
+
+	for _, childId := range children {
+		if parent, ok := w.Hierarchy().Parent(childId); !ok || parent != parentId {
+			w.SetParent(childId, parentId)
+		}
+	}
+
+	w.updateChildren(parentId, func(target *Children) {
+		for idx, childId := range children {
+			target.moveChild(childId, idx)
+		}
+	})
+
+	return nil
+}
+
+// updateChildren replaces the Children component of the given entity
+// with an updated copy.
+func (w *World) updateChildren(parentId EntityId, update func(children *Children)) {
+	entity, ok := w.storage.Get(parentId)
+	if !ok {
+		return
+	}
+
+	children, ok := entity.Get(spoke.ComponentTypeOf[Children]()).(*Children)
+	if !ok {
+		return
+	}
+
+	updated := copyComponent(children).(*Children)
+	update(updated)
+
+	w.storage.InsertComponent(w.currentTick, parentId, updated)
+}
+
+// SetParent makes this entity a child of the given parent.
+// If this would create a cycle in the hierarchy, the command is skipped.
+// See World.SetParent.
+func (e EntityCommands) SetParent(parentId EntityId) EntityCommands {
+	e.commands.Add(CommandFn(func(world *World) {
+		logHierarchyCommandError(world.trySetParent(e.entityId, parentId))
+	}))
+
+	return e
+}
+
+// RemoveParent removes this entity from its parent.
+// See World.RemoveParent.
+func (e EntityCommands) RemoveParent() EntityCommands {
+	e.commands.Add(CommandFn(func(world *World) { world.RemoveParent(e.entityId) }))
+	return e
+}
+
+// InsertChildAt makes childId a child of this entity at the given index.
+// If this would create a cycle in the hierarchy, the command is skipped.
+// See World.InsertChildAt.
+func (e EntityCommands) InsertChildAt(index int, childId EntityId) EntityCommands {
+	e.commands.Add(CommandFn(func(world *World) {
+		logHierarchyCommandError(world.tryInsertChildAt(e.entityId, index, childId))
+	}))
+
+	return e
+}
+
+// ReplaceChildren replaces all children of this entity.
+// If this would create a cycle in the hierarchy, the command is skipped.
+// See World.ReplaceChildren.
+func (e EntityCommands) ReplaceChildren(children ...EntityId) EntityCommands {
+	e.commands.Add(CommandFn(func(world *World) {
+		logHierarchyCommandError(world.tryReplaceChildren(e.entityId, children))
+	}))
+
+	return e
+}
+
+func logHierarchyCommandError(err error) {
+	if err != nil {
+		slog.Warn("Skipping hierarchy command", slog.String("err", err.Error()))
+	}
+}
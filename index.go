@@ -0,0 +1,238 @@
+package byke
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// Index maps keys of type K, extracted from a component C, to the entities holding
+// a component with that key. Register an index using App.IndexComponent. The Index
+// is a resource and can be requested directly as a system parameter. Use QueryByIndex
+// to filter a query by a key of the index.
+//
+// The index is updated immediately when C is inserted, replaced or removed and when an
+// entity is despawned. Changes made through a pointer to C are picked up by a system running
+// in First and PostUpdate. Until then, Lookup already skips entities that do not hold the key
+// anymore, but does not yet return entities whose value was changed to the key.
+type Index[C spoke.IsSupportsChangeDetectionComponent[C], K comparable] struct {
+	state *indexState[C, K]
+}
+
+type indexState[C any, K comparable] struct {
+	world         *World
+	componentType *ComponentType
+
+	keyOf    func(value C) K
+	entities map[K][]EntityId
+	keys     map[EntityId]K
+}
+
+// IndexComponent registers an Index for the component C using the keys returned by keyOf.
+// To index a comparable component by its value, pass a function returning the value itself.
+func (a *App) IndexComponent[C spoke.IsSupportsChangeDetectionComponent[C], K comparable](keyOf func(value C) K) {
+	a.AddPlugin(pluginIndex[C, K](keyOf))
+}
+
+func pluginIndex[C spoke.IsSupportsChangeDetectionComponent[C], K comparable](keyOf func(value C) K) Plugin {
+	return func(app *App) {
+		world := app.World()
+
+		if _, ok := world.ResourceOf[Index[C, K]](); ok {
+			var cZero C
+			panic(fmt.Sprintf("component %T is already indexed", cZero))
+		}
+
+		state := &indexState[C, K]{
+			world:         world,
+			componentType: spoke.ComponentTypeOf[C](),
+			keyOf:         keyOf,
+			entities:      map[K][]EntityId{},
+			keys:          map[EntityId]K{},
+		}
+
+		// keep the index up to date without occupying the hooks of C
+		world.storage.AddInternalComponentHooks(state.componentType, spoke.ComponentHooks{
+			OnInsert: func(entity spoke.EntityRef, componentType *ComponentType) {
+				state.update(entity.EntityId(), *any(entity.Get(componentType)).(*C))
+			},
+			OnDiscard: func(entity spoke.EntityRef, componentType *ComponentType) {
+				state.remove(entity.EntityId())
+			},
+		})
+
+		// index all entities that already exist
+		existing := world.Query[indexItem[C]]()
+		for item := range existing.Items() {
+			state.update(item.EntityId, item.Value)
+		}
+
+		app.InsertResource(Index[C, K]{state: state})
+		app.AddSystems(First, System(syncIndexSystem[C, K]).Internal())
+		app.AddSystems(PostUpdate, System(syncIndexSystem[C, K]).Internal())
+	}
+}
+
+type indexItem[C spoke.IsSupportsChangeDetectionComponent[C]] struct {
+	_        Allow[Disabled]
+	EntityId EntityId
+	Value    C
+}
+
+// syncIndexSystem updates the index for component values
+// that were modified through a pointer.
+func syncIndexSystem[C spoke.IsSupportsChangeDetectionComponent[C], K comparable](
+	index Index[C, K],
+	query Query[struct {
+		_        Allow[Disabled]
+		_        Changed[C]
+		EntityId EntityId
+		Value    C
+	}],
+) {
+	for item := range query.Items() {
+		index.state.update(item.EntityId, item.Value)
+	}
+}
+
+func (s *indexState[C, K]) update(entityId EntityId, value C) {
+	key := s.keyOf(value)
+
+	if previous, ok := s.keys[entityId]; ok {
+		if previous == key {
+			return
+		}
+
+		s.remove(entityId)
+	}
+
+	s.keys[entityId] = key
+	s.entities[key] = append(s.entities[key], entityId)
+}
+
+func (s *indexState[C, K]) remove(entityId EntityId) {
+	key, ok := s.keys[entityId]
+	if !ok {
+		return
+	}
+
+	delete(s.keys, entityId)
+
+	entities := slices.DeleteFunc(s.entities[key], func(id EntityId) bool { return id == entityId })
+	if len(entities) == 0 {
+		delete(s.entities, key)
+	} else {
+		s.entities[key] = entities
+	}
+}
+
+// dropStale re-indexes entities of the given key that do not hold a component
+// with that key anymore, as their component was modified through a pointer.
+func (s *indexState[C, K]) dropStale(key K) {
+	var stale []EntityId
+
+	for _, entityId := range s.entities[key] {
+		value, ok := s.valueOf(entityId)
+		if !ok || s.keyOf(value) != key {
+			stale = append(stale, entityId)
+		}
+	}
+
+	for _, entityId := range stale {
+		if value, ok := s.valueOf(entityId); ok {
+			s.update(entityId, value)
+		} else {
+			s.remove(entityId)
+		}
+	}
+}
+
+func (s *indexState[C, K]) valueOf(entityId EntityId) (C, bool) {
+	entity, ok := s.world.Entity(entityId)
+	if !ok || !entity.Has(s.componentType) {
+		var cZero C
+		return cZero, false
+	}
+
+	return *any(entity.Get(s.componentType)).(*C), true
+}
+
+// Lookup returns the entities with a component C matching the given key.
+// You **must not** modify the returned slice.
+func (i Index[C, K]) Lookup(key K) []EntityId {
+	i.state.dropStale(key)
+	return i.state.entities[key]
+}
+
+// Contains returns true, if at least one entity has a component C matching the given key.
+func (i Index[C, K]) Contains(key K) bool {
+	return len(i.Lookup(key)) > 0
+}
+
+// Matching yields the items of the query for all entities with a component
+// matching the given key. Entities not matching the query are skipped.
+func (i Index[C, K]) Matching[T any](query *Query[T], key K) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, entityId := range i.Lookup(key) {
+			item, ok := query.Get(entityId)
+			if !ok {
+				continue
+			}
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// QueryByIndex is a SystemParam that combines a Query with the Index of a component C.
+// Use At to only match the entities with a component C matching a key. The Index
+// for C must be registered using App.IndexComponent.
+type QueryByIndex[C spoke.IsSupportsChangeDetectionComponent[C], K comparable, T any] struct {
+	index Index[C, K]
+	query *Query[T]
+}
+
+// At yields the items of the query for all entities with a
+// component C matching the given key.
+func (q QueryByIndex[C, K, T]) At(key K) iter.Seq[T] {
+	return q.index.Matching(q.query, key)
+}
+
+// Query returns the underlying query, not filtered by the index.
+func (q QueryByIndex[C, K, T]) Query() *Query[T] {
+	return q.query
+}
+
+func (QueryByIndex[C, K, T]) newState(world *World, _ queryByIndexT) SystemParamState {
+	// instantiate a query that we can delegate to
+	var query Query[T]
+	queryState := query.newState(world, &query)
+
+	var value QueryByIndex[C, K, T]
+
+	return &singleParamState{
+		QueryState: queryState,
+		Type:       reflect.TypeFor[QueryByIndex[C, K, T]](),
+		extractValue: func(q reflect.Value) (reflect.Value, error) {
+			index, ok := world.ResourceOf[Index[C, K]]()
+			if !ok {
+				var cZero C
+				panic(fmt.Sprintf("component %T is not indexed", cZero))
+			}
+
+			value.index = *index
+			value.query = q.Addr().Interface().(*Query[T])
+
+			return reflect.ValueOf(&value).Elem(), nil
+		},
+	}
+}
+
+type queryByIndexT interface {
+	newState(world *World, _ queryByIndexT) SystemParamState
+}
@@ -0,0 +1,116 @@
+package byke
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexComponent(t *testing.T) {
+	var app App
+
+	w := app.World()
+	existing := w.Spawn([]ErasedComponent{Named("Tree")})
+
+	app.IndexComponent[Name](func(value Name) Name { return value })
+	app.IndexComponent[Position](func(value Position) int { return value.X })
+
+	a := w.Spawn([]ErasedComponent{Named("Tree"), Position{X: 1}})
+	b := w.Spawn([]ErasedComponent{Named("Rock"), Position{X: 1}})
+
+	w.RunSystem(func(names Index[Name, Name], positions Index[Position, int]) {
+		require.Equal(t, []EntityId{existing, a}, names.Lookup(Named("Tree")))
+		require.Equal(t, []EntityId{b}, names.Lookup(Named("Rock")))
+		require.Equal(t, []EntityId{a, b}, positions.Lookup(1))
+	})
+
+	// replace, remove and despawn
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(existing).Insert(Named("Rock"))
+		commands.Entity(a).Remove[Name]()
+		commands.Entity(b).Despawn()
+	})
+
+	// the index is updated immediately
+	w.RunSystem(func(names Index[Name, Name], positions Index[Position, int]) {
+		require.Empty(t, names.Lookup(Named("Tree")))
+		require.Equal(t, []EntityId{existing}, names.Lookup(Named("Rock")))
+		require.Equal(t, []EntityId{a}, positions.Lookup(1))
+	})
+
+	// modify through a pointer
+	w.RunSystem(func(q Query[*Position]) {
+		for pos := range q.Items() {
+			pos.X = 2
+		}
+	})
+
+	// the new key is only known after the index was synced, but the
+	// entity is not returned for the old key anymore
+	w.RunSystem(func(positions Index[Position, int]) {
+		require.False(t, positions.Contains(2))
+		require.Empty(t, positions.Lookup(1))
+	})
+
+	w.RunSchedule(PostUpdate)
+
+	w.RunSystem(func(positions Index[Position, int], q Query[Name]) {
+		require.Empty(t, positions.Lookup(1))
+		require.Equal(t, []EntityId{a}, positions.Lookup(2))
+		require.True(t, positions.Contains(2))
+
+		var matches int
+		for range positions.Matching(&q, 2) {
+			matches++
+		}
+
+		// a has no Name anymore
+		require.Equal(t, 0, matches)
+	})
+}
+
+func TestIndexComponentKeepsHooks(t *testing.T) {
+	var app App
+
+	app.IndexComponent[Name](func(value Name) Name { return value })
+
+	// the index must not occupy the hooks of the component
+	var inserted int
+	app.World().RegisterComponentHooks[Name]().
+		OnInsert(func(world DeferredWorld, entity EntityRef, componentType *ComponentType) {
+			inserted++
+		})
+
+	app.World().Spawn([]ErasedComponent{Named("Tree")})
+	require.Equal(t, 1, inserted)
+}
+
+func TestQueryByIndex(t *testing.T) {
+	var app App
+
+	app.IndexComponent[Position](func(value Position) int { return value.X })
+
+	w := app.World()
+	a := w.Spawn([]ErasedComponent{Named("A"), Position{X: 1}})
+	_ = w.Spawn([]ErasedComponent{Named("B"), Position{X: 2}})
+	c := w.Spawn([]ErasedComponent{Position{X: 1}})
+	d := w.Spawn([]ErasedComponent{Named("D"), Position{X: 1}})
+
+	w.RunSystem(func(q QueryByIndex[Position, int, struct {
+		EntityId EntityId
+		Name     Name
+	}]) {
+		var entityIds []EntityId
+		for item := range q.At(1) {
+			entityIds = append(entityIds, item.EntityId)
+		}
+
+		// c has no name and is not matched by the query
+		require.Equal(t, []EntityId{a, d}, entityIds)
+		require.NotContains(t, entityIds, c)
+
+		require.Empty(t, slices.Collect(q.At(3)))
+		require.Equal(t, 3, q.Query().Count())
+	})
+}
@@ -151,7 +151,18 @@ func (Without[C]) applyTo(result *ParsedQuery, fieldOffset uintptr) spoke.Filter
 	}
 }
 
-type Changed[C spoke.IsSupportsChangeDetectionComponent[C]] struct{}
+// Allow opts the query in to match entities with the component C, even if
+// those are excluded by default, e.g. entities with the spoke.Disabled component.
+type Allow[C spoke.IsComponent[C]] struct{}
+
+func (Allow[C]) embeddable(isEmbeddableMarker) {}
+
+func (Allow[C]) applyTo(result *ParsedQuery, fieldOffset uintptr) spoke.Filter {
+	result.Builder.Allow(spoke.ComponentTypeOf[C]())
+	return spoke.Filter{}
+}
+
+type Changed[C spoke.IsComponent[C]] struct{}
 
 func (Changed[C]) embeddable(isEmbeddableMarker) {}
 
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// fieldRef is the common memory layout of query fields that need
+// access to the entity and the QueryContext, such as Mut.
+type fieldRef struct {
+	value         unsafe.Pointer
+	entity        spoke.EntityRef
+	componentType *spoke.ComponentType
+	context       spoke.QueryContext
+}
+
+func applyFieldRef[C spoke.IsComponent[C]](result *ParsedQuery, fieldOffset uintptr, optional bool) int {
+	componentType := spoke.ComponentTypeOf[C]()
+	idx := result.Builder.FetchComponent(componentType, optional)
+
+	result.Setters = append(result.Setters, Setter{
+		UnsafeFieldOffset: fieldOffset,
+		UseFieldRef:       true,
+		ComponentIdx:      idx,
+		ComponentType:     componentType,
+	})
+
+	return idx
+}
+
+// Mut fetches a component for mutation. Accessing the component using Get marks
+// it as changed, which makes change detection work for components that are
+// not comparable.
+type Mut[C spoke.IsComponent[C]] struct {
+	ref fieldRef
+}
+
+func (Mut[C]) applyTo(result *ParsedQuery, fieldOffset uintptr) spoke.Filter {
+	componentType := spoke.ComponentTypeOf[C]()
+	if componentType.IsImmutableComponent {
+		panic(fmt.Sprintf("Can not use Mut with ImmutableComponent %s", componentType))
+	}
+
+	// keep the shadow values of comparable components in sync
+	result.Mutable = append(result.Mutable, componentType)
+
+	applyFieldRef[C](result, fieldOffset, false)
+
+	return spoke.Filter{}
+}
+
+// Get returns a pointer to the component and marks it as changed.
+func (m *Mut[C]) Get() *C {
+	m.SetChanged()
+	return (*C)(m.ref.value)
+}
+
+// Read returns a copy of the component value without marking it as changed.
+func (m *Mut[C]) Read() C {
+	return *(*C)(m.ref.value)
+}
+
+// SetChanged marks the component as changed.
+func (m *Mut[C]) SetChanged() {
+	m.ref.entity.MarkChanged(m.ref.componentType, m.ref.context.ThisRun)
+}
+
+// BypassChangeDetection returns a pointer to the component without marking it as changed.
+func (m *Mut[C]) BypassChangeDetection() *C {
+	return (*C)(m.ref.value)
+}
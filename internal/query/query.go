@@ -35,9 +35,14 @@ type Setter struct {
 	// UseEntityRef implies that UnsafeFieldOffset is a pointer to an EntityId variable
 	// and we're supposed to copy the value of the current EntityId into that field.
 	UseEntityRef bool
+
+	// UseFieldRef implies that UnsafeFieldOffset is a pointer to a fieldRef
+	// that needs to be filled with the component of type ComponentType.
+	UseFieldRef   bool
+	ComponentType *spoke.ComponentType
 }
 
-func FromEntity[T any](setters []Setter, ref spoke.EntityRef) T {
+func FromEntity[T any](setters []Setter, ctx spoke.QueryContext, ref spoke.EntityRef) T {
 	var target T
 
 	ptrToTarget := unsafe.Pointer(&target)
@@ -75,6 +80,15 @@ func FromEntity[T any](setters []Setter, ref spoke.EntityRef) T {
 		case setter.UseEntityRef:
 			target := unsafe.Add(ptrToTarget, setter.UnsafeFieldOffset)
 			*(*spoke.EntityRef)(target) = ref
+
+		case setter.UseFieldRef:
+			target := unsafe.Add(ptrToTarget, setter.UnsafeFieldOffset)
+			*(*fieldRef)(target) = fieldRef{
+				value:         ref.GetAt(setter.ComponentIdx),
+				entity:        ref,
+				componentType: setter.ComponentType,
+				context:       ctx,
+			}
 		}
 	}
 
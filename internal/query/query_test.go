@@ -439,7 +439,7 @@ func runTestFromEntity[Q any](t *testing.T, entity spoke.EntityRef, expected Q)
 		parsed, err := ParseQuery(reflect.TypeFor[Q]())
 		require.NoError(t, err)
 
-		queryTarget := FromEntity[Q](parsed.Setters, entity)
+		queryTarget := FromEntity[Q](parsed.Setters, spoke.QueryContext{}, entity)
 		require.Equal(t, expected, queryTarget)
 	})
 }
@@ -473,6 +473,6 @@ func BenchmarkFromEntity(b *testing.B) {
 	b.ResetTimer()
 
 	for b.Loop() {
-		FromEntity[QueryItem](query.Setters, entity)
+		FromEntity[QueryItem](query.Setters, spoke.QueryContext{}, entity)
 	}
 }
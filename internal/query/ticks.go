@@ -0,0 +1,43 @@
+package query
+
+import (
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// Ticks provides the ticks at which a component was added and last changed.
+// It does not fetch the component value itself.
+type Ticks[C spoke.IsComponent[C]] struct {
+	ref fieldRef
+}
+
+func (Ticks[C]) applyTo(result *ParsedQuery, fieldOffset uintptr) spoke.Filter {
+	applyFieldRef[C](result, fieldOffset, false)
+	return spoke.Filter{}
+}
+
+// Added returns the tick at which the component was added to the entity.
+func (t *Ticks[C]) Added() spoke.Tick {
+	return t.ref.entity.Added(t.ref.componentType)
+}
+
+// Changed returns the tick at which the component was last changed.
+func (t *Ticks[C]) Changed() spoke.Tick {
+	return t.ref.entity.Changed(t.ref.componentType)
+}
+
+// IsAdded returns true, if the component was added since the last
+// run of the system owning the query.
+func (t *Ticks[C]) IsAdded() bool {
+	return t.isNewerThanLastRun(t.Added())
+}
+
+// IsChanged returns true, if the component was changed since the last
+// run of the system owning the query.
+func (t *Ticks[C]) IsChanged() bool {
+	return t.isNewerThanLastRun(t.Changed())
+}
+
+func (t *Ticks[C]) isNewerThanLastRun(tick spoke.Tick) bool {
+	// same logic as in the Added and Changed filters
+	return tick != spoke.NoTick && tick >= t.ref.context.LastRun
+}
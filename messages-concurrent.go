@@ -0,0 +1,157 @@
+package byke
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Backpressure defines how a ConcurrentMessageSender behaves if its
+// queue is full when sending a new message.
+type Backpressure uint8
+
+const (
+	// BackpressureUnbounded never limits the queue. Sending never blocks
+	// and never drops messages.
+	BackpressureUnbounded Backpressure = iota
+
+	// BackpressureDropOldest drops the oldest queued message if the queue is full.
+	BackpressureDropOldest
+
+	// BackpressureBlock blocks the sender until the queue is drained by the
+	// schedule and space for the new message becomes available.
+	BackpressureBlock
+)
+
+// ConcurrentMessageConfig configures a ConcurrentMessageSender
+// registered using App.AddConcurrentMessage.
+type ConcurrentMessageConfig struct {
+	Backpressure Backpressure
+
+	// Capacity is the maximum number of messages queued between two drains.
+	// It is ignored for BackpressureUnbounded.
+	Capacity int
+}
+
+func pluginConcurrentMessage[E any](config ConcurrentMessageConfig) Plugin {
+	if config.Backpressure != BackpressureUnbounded && config.Capacity <= 0 {
+		panic(fmt.Errorf("capacity must be positive for backpressure mode %d", config.Backpressure))
+	}
+
+	return func(app *App) {
+		if _, ok := app.World().ResourceOf[Messages[E]](); !ok {
+			app.AddMessage[E]()
+		}
+
+		app.InsertResource(ConcurrentMessageSender[E]{
+			queue: newConcurrentMessageQueue[E](config),
+		})
+
+		app.AddSystems(First, System(drainConcurrentMessagesSystem[E]).Internal())
+	}
+}
+
+func drainConcurrentMessagesSystem[E any](sender ConcurrentMessageSender[E], messages *Messages[E]) {
+	for _, message := range sender.queue.drain() {
+		messages.Send(message)
+	}
+}
+
+// ConcurrentMessageSender sends messages into the world from any goroutine.
+// Messages are queued and moved into Messages at the start of
+// the next frame, during the First schedule.
+//
+// A ConcurrentMessageSender is a small handle that can be copied freely.
+// Acquire one using World.ConcurrentMessageSender or by requesting it as a system parameter.
+type ConcurrentMessageSender[E any] struct {
+	queue *concurrentMessageQueue[E]
+}
+
+// Send queues the given message. Depending on the configured Backpressure,
+// this might block or drop an older message.
+func (s ConcurrentMessageSender[E]) Send(message E) {
+	s.queue.push(message, true)
+}
+
+// TrySend queues the given message without ever blocking. It returns false, if the
+// message could not be queued because the queue was full in BackpressureBlock mode.
+func (s ConcurrentMessageSender[E]) TrySend(message E) bool {
+	return s.queue.push(message, false)
+}
+
+// Dropped returns the number of messages that were dropped
+// so far due to BackpressureDropOldest.
+func (s ConcurrentMessageSender[E]) Dropped() int {
+	s.queue.mutex.Lock()
+	defer s.queue.mutex.Unlock()
+
+	return s.queue.dropped
+}
+
+// ConcurrentMessageSender returns the ConcurrentMessageSender for messages of type E.
+// The message type must have been registered using App.AddConcurrentMessage.
+func (w *World) ConcurrentMessageSender[E any]() ConcurrentMessageSender[E] {
+	sender, ok := w.ResourceOf[ConcurrentMessageSender[E]]()
+	if !ok {
+		var eZero E
+		panic(fmt.Sprintf("concurrent message %T not registered", eZero))
+	}
+
+	return *sender
+}
+
+type concurrentMessageQueue[E any] struct {
+	mutex    sync.Mutex
+	notFull  sync.Cond
+	config   ConcurrentMessageConfig
+	messages []E
+	spare    []E
+	dropped  int
+}
+
+func newConcurrentMessageQueue[E any](config ConcurrentMessageConfig) *concurrentMessageQueue[E] {
+	queue := &concurrentMessageQueue[E]{config: config}
+	queue.notFull.L = &queue.mutex
+	return queue
+}
+
+func (q *concurrentMessageQueue[E]) push(message E, block bool) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	switch q.config.Backpressure {
+	case BackpressureDropOldest:
+		if len(q.messages) >= q.config.Capacity {
+			// shift instead of re-slicing, so we keep using the same memory
+			copy(q.messages, q.messages[1:])
+			q.messages = q.messages[:len(q.messages)-1]
+			q.dropped += 1
+		}
+
+	case BackpressureBlock:
+		for len(q.messages) >= q.config.Capacity {
+			if !block {
+				return false
+			}
+
+			q.notFull.Wait()
+		}
+	}
+
+	q.messages = append(q.messages, message)
+	return true
+}
+
+func (q *concurrentMessageQueue[E]) drain() []E {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	messages := q.messages
+
+	// swap buffers, the returned slice stays valid until the next drain
+	clear(q.spare)
+	q.messages, q.spare = q.spare[:0], messages
+
+	q.notFull.Broadcast()
+
+	return messages
+}
@@ -2,21 +2,98 @@ package byke
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
+	"time"
 
 	"github.com/oliverbestmann/byke/internal/refl"
 )
 
-func pluginMessage[E any](app *App) {
-	app.InitResource[Messages[E]]()
+// MessageUpdatePolicy defines when the buffers of Messages are swapped. A message
+// can be read until the second swap after it was sent.
+type MessageUpdatePolicy int
+
+const (
+	// MessageUpdateFrame swaps the buffers at the end of every frame. Use it for
+	// messages sent and read in the schedules running once per frame.
+	MessageUpdateFrame MessageUpdatePolicy = iota
+
+	// MessageUpdateFixed swaps the buffers at the end of a frame, but only if at least one
+	// fixed step ran since the previous swap. Use it for messages sent or read in the
+	// FixedMain schedules. Readers in fixed schedules do not miss messages if no fixed
+	// step runs in a frame, and readers in Update see all messages sent in FixedUpdate.
+	// If fixed time does not progress for two frames, e.g. while virtual time is paused,
+	// the buffers are swapped anyway.
+	MessageUpdateFixed
+
+	// MessageUpdateManual never swaps the buffers automatically. Call Messages.Update
+	// to drop old messages.
+	MessageUpdateManual
+)
+
+// MessageConfig configures messages added using App.AddMessage.
+type MessageConfig struct {
+	UpdatePolicy MessageUpdatePolicy
+}
+
+func pluginMessage[E any](config MessageConfig) Plugin {
+	return func(app *App) {
+		app.InitResource[Messages[E]]()
+
+		switch config.UpdatePolicy {
+		case MessageUpdateFrame:
+			app.AddSystems(Last, System(updateMessagesSystem[E]).Internal())
 
-	app.AddSystems(Last, updateMessagesSystem[E])
+		case MessageUpdateFixed:
+			app.AddSystems(Last, System(updateFixedMessagesSystem[E]).Internal())
+
+		case MessageUpdateManual:
+			// updated by the user
+
+		default:
+			panic(fmt.Errorf("unknown message update policy %d", config.UpdatePolicy))
+		}
+	}
 }
 
 func updateMessagesSystem[E any](messages *Messages[E]) {
 	messages.Update()
 }
 
+// fixedMessagesState remembers the FixedTime at the previous run of updateFixedMessagesSystem.
+type fixedMessagesState struct {
+	steps    int
+	overstep time.Duration
+
+	// number of frames in which fixed time did not progress
+	stalledFrames int
+}
+
+func updateFixedMessagesSystem[E any](messages *Messages[E], ft FixedTime, state *Local[fixedMessagesState]) {
+	switch {
+	case ft.steps != state.Value.steps:
+		state.Value.stalledFrames = 0
+		messages.Update()
+
+	case ft.overstep == state.Value.overstep:
+		// fixed time does not progress, e.g. because virtual time is paused. No fixed
+		// step will run any time soon, do not let the messages accumulate.
+		state.Value.stalledFrames += 1
+
+		if state.Value.stalledFrames >= 2 {
+			state.Value.stalledFrames = 0
+			messages.Update()
+		}
+
+	default:
+		// fixed time progresses towards the next step
+		state.Value.stalledFrames = 0
+	}
+
+	state.Value.steps = ft.steps
+	state.Value.overstep = ft.overstep
+}
+
 type MessageId int
 
 type MessageWithId[M any] struct {
@@ -139,6 +216,51 @@ func (r *MessageReader[E]) Read() []E {
 	return messages
 }
 
+// Len returns the number of messages not yet read.
+func (r *MessageReader[E]) Len() int {
+	// counting must not mark the messages as read
+	lastId := r.lastId
+	defer func() { r.lastId = lastId }()
+
+	var count int
+	for range r.Items() {
+		count++
+	}
+
+	return count
+}
+
+// IsEmpty returns true, if there are no messages left to read.
+func (r *MessageReader[E]) IsEmpty() bool {
+	return r.Len() == 0
+}
+
+// Clear marks all messages as read.
+func (r *MessageReader[E]) Clear() {
+	r.lastId = max(r.lastId, r.messages.prevId)
+}
+
+// Items returns an iterator over all messages not yet read, together with their ids.
+// Each message is marked as read once it was yielded. If the iteration stops early,
+// the remaining messages can still be read later.
+func (r *MessageReader[E]) Items() iter.Seq2[MessageId, E] {
+	return func(yield func(MessageId, E) bool) {
+		for _, buffer := range [][]MessageWithId[E]{r.messages.prev, r.messages.curr} {
+			for _, message := range buffer {
+				if message.Id <= r.lastId {
+					continue
+				}
+
+				r.lastId = message.Id
+
+				if !yield(message.Id, message.Message) {
+					return
+				}
+			}
+		}
+	}
+}
+
 func (r *MessageReader[E]) newState(world *World, _ messageReaderT) SystemParamState {
 	messages, ok := world.ResourceOf[Messages[E]]()
 	if !ok {
@@ -1,7 +1,9 @@
 package byke
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -73,3 +75,233 @@ func TestMessages(t *testing.T) {
 	expected = []MyMessage{8}
 	w.RunSystem(reader)
 }
+
+func TestConcurrentMessageSender(t *testing.T) {
+	var app App
+
+	app.AddConcurrentMessage[MyMessage]()
+
+	w := app.World()
+	sender := w.ConcurrentMessageSender[MyMessage]()
+
+	var wg sync.WaitGroup
+	for idx := range 10 {
+		wg.Go(func() { sender.Send(MyMessage(idx)) })
+	}
+
+	wg.Wait()
+
+	var received []MyMessage
+	reader := func(r *MessageReader[MyMessage]) {
+		received = append(received, r.Read()...)
+	}
+
+	// not yet visible before the First schedule ran
+	w.RunSystem(reader)
+	require.Empty(t, received)
+
+	w.RunSchedule(First)
+	w.RunSystem(reader)
+	require.ElementsMatch(t, []MyMessage{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, received)
+}
+
+func TestConcurrentMessageSenderDropOldest(t *testing.T) {
+	var app App
+
+	app.AddConcurrentMessage[MyMessage](ConcurrentMessageConfig{
+		Backpressure: BackpressureDropOldest,
+		Capacity:     2,
+	})
+
+	w := app.World()
+	sender := w.ConcurrentMessageSender[MyMessage]()
+
+	sender.Send(1)
+	sender.Send(2)
+	sender.Send(3)
+
+	require.Equal(t, 1, sender.Dropped())
+
+	w.RunSchedule(First)
+
+	w.RunSystem(func(r *MessageReader[MyMessage]) {
+		require.Equal(t, []MyMessage{2, 3}, r.Read())
+	})
+}
+
+func TestConcurrentMessageSenderBlock(t *testing.T) {
+	var app App
+
+	app.AddConcurrentMessage[MyMessage](ConcurrentMessageConfig{
+		Backpressure: BackpressureBlock,
+		Capacity:     1,
+	})
+
+	w := app.World()
+	sender := w.ConcurrentMessageSender[MyMessage]()
+
+	require.True(t, sender.TrySend(1))
+	require.False(t, sender.TrySend(2))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sender.Send(2)
+	}()
+
+	// draining makes space for the blocked sender
+	w.RunSchedule(First)
+	<-done
+
+	w.RunSchedule(First)
+
+	w.RunSystem(func(r *MessageReader[MyMessage]) {
+		require.Equal(t, []MyMessage{1, 2}, r.Read())
+	})
+}
+
+func TestMessageReaderCursor(t *testing.T) {
+	var app App
+	app.AddMessage[MyMessage]()
+
+	w := app.World()
+	messages := w.RequireResourceOf[Messages[MyMessage]]()
+	reader := messages.Reader()
+
+	require.True(t, reader.IsEmpty())
+
+	messages.Send(1)
+	messages.Send(2)
+	messages.Send(3)
+
+	require.Equal(t, 3, reader.Len())
+
+	// stop after the first message, the others stay unread
+	for id, message := range reader.Items() {
+		require.Equal(t, MessageId(1), id)
+		require.Equal(t, MyMessage(1), message)
+		break
+	}
+
+	require.Equal(t, 2, reader.Len())
+	require.Equal(t, []MyMessage{2, 3}, reader.Read())
+	require.True(t, reader.IsEmpty())
+
+	messages.Send(4)
+	reader.Clear()
+	require.True(t, reader.IsEmpty())
+}
+
+type FrameMessage int
+
+type FixedMessage int
+
+type FixedSentMessage int
+
+func TestMessagesAcrossSchedules(t *testing.T) {
+	var app App
+
+	app.AddMessage[FrameMessage]()
+	app.AddMessage[FixedMessage](MessageConfig{UpdatePolicy: MessageUpdateFixed})
+	app.AddMessage[FixedSentMessage](MessageConfig{UpdatePolicy: MessageUpdateFixed})
+
+	// run a fixed step every third frame
+	app.InsertResource(FakeClock{Step: time.Second / 60})
+	app.InsertResource(FixedTime{StepInterval: time.Second / 20})
+
+	var frame, step int
+	var sentInFrame, receivedInFixed, receivedInFixedFramePolicy []int
+	var sentInFixed, receivedInFrame []int
+
+	app.AddSystems(Update, func(fixed *MessageWriter[FixedMessage], frames *MessageWriter[FrameMessage], fixedSent *MessageReader[FixedSentMessage]) {
+		frame += 1
+		sentInFrame = append(sentInFrame, frame)
+
+		fixed.Write(FixedMessage(frame))
+		frames.Write(FrameMessage(frame))
+
+		for _, message := range fixedSent.Items() {
+			receivedInFrame = append(receivedInFrame, int(message))
+		}
+	})
+
+	app.AddSystems(FixedUpdate, func(fixed *MessageReader[FixedMessage], frames *MessageReader[FrameMessage], fixedSent *MessageWriter[FixedSentMessage]) {
+		for _, message := range fixed.Items() {
+			receivedInFixed = append(receivedInFixed, int(message))
+		}
+
+		for _, message := range frames.Items() {
+			receivedInFixedFramePolicy = append(receivedInFixedFramePolicy, int(message))
+		}
+
+		// one message per step, Update must see it even if the next frame runs no step
+		step += 1
+		sentInFixed = append(sentInFixed, step)
+		fixedSent.Write(FixedSentMessage(step))
+	})
+
+	w := app.World()
+	for range 30 {
+		w.RunSchedule(Main)
+	}
+
+	require.NotEmpty(t, receivedInFixed)
+
+	// the fixed reader sees every message sent before the last fixed step
+	require.Equal(t, sentInFrame[:len(receivedInFixed)], receivedInFixed)
+	require.GreaterOrEqual(t, len(receivedInFixed), len(sentInFrame)-3)
+
+	// with per frame updates, messages are dropped before a fixed step runs
+	require.Less(t, len(receivedInFixedFramePolicy), len(receivedInFixed))
+
+	// messages sent in FixedUpdate are all seen by Update
+	require.Equal(t, sentInFixed, receivedInFrame)
+}
+
+func TestMessagesManualUpdate(t *testing.T) {
+	var app App
+	app.AddMessage[MyMessage](MessageConfig{UpdatePolicy: MessageUpdateManual})
+
+	w := app.World()
+	messages := w.RequireResourceOf[Messages[MyMessage]]()
+	messages.Send(1)
+
+	w.RunSchedule(Last)
+	w.RunSchedule(Last)
+
+	// still readable, buffers were never swapped
+	require.Equal(t, 1, messages.Reader().Len())
+
+	messages.Update()
+	messages.Update()
+	require.True(t, messages.Reader().IsEmpty())
+}
+
+func TestFixedMessagesWhilePaused(t *testing.T) {
+	var app App
+
+	app.AddMessage[FixedMessage](MessageConfig{UpdatePolicy: MessageUpdateFixed})
+	app.InsertResource(FakeClock{Step: time.Second / 60})
+
+	app.AddSystems(Update, func(fixed *MessageWriter[FixedMessage]) {
+		fixed.Write(1)
+	})
+
+	w := app.World()
+
+	// run a few frames so fixed time is running
+	for range 5 {
+		w.RunSchedule(Main)
+	}
+
+	// pause virtual time, no fixed steps will run from now on
+	w.RequireResourceOf[VirtualTime]().Scale = 0
+
+	for range 30 {
+		w.RunSchedule(Main)
+	}
+
+	// messages must not accumulate while no fixed step runs
+	messages := w.RequireResourceOf[Messages[FixedMessage]]()
+	require.LessOrEqual(t, len(messages.AppendTo(nil)), 4)
+}
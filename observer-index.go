@@ -0,0 +1,122 @@
+package byke
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+var observerComponentType = spoke.ComponentTypeOf[Observer]()
+
+// observerKey identifies the observers of an event type. Global observers
+// are keyed using NoEntityId as target.
+type observerKey struct {
+	EventType reflect.Type
+	Target    EntityId
+}
+
+type observerEntry struct {
+	Keys   []observerKey
+	System *preparedSystem
+}
+
+// observerIndex maps event types and target entities to the observers
+// interested in them. It is kept up to date by the World when an Observer
+// component is inserted or removed, see World.onComponentInsert.
+type observerIndex struct {
+	byKey      map[observerKey][]EntityId
+	byObserver map[EntityId]observerEntry
+
+	// observer entities watching a target entity
+	byTarget map[EntityId][]EntityId
+}
+
+func newObserverIndex() *observerIndex {
+	return &observerIndex{
+		byKey:      map[observerKey][]EntityId{},
+		byObserver: map[EntityId]observerEntry{},
+		byTarget:   map[EntityId][]EntityId{},
+	}
+}
+
+func (idx *observerIndex) insert(observerId EntityId, observer *Observer) {
+	// the observer component might have been replaced
+	idx.remove(observerId)
+
+	entry := observerEntry{System: observer.system}
+
+	if observer.IsScoped() {
+		for _, target := range observer.entities {
+			entry.Keys = append(entry.Keys, observerKey{EventType: observer.eventType, Target: target})
+			idx.byTarget[target] = append(idx.byTarget[target], observerId)
+		}
+	} else {
+		entry.Keys = append(entry.Keys, observerKey{EventType: observer.eventType})
+	}
+
+	for _, key := range entry.Keys {
+		idx.byKey[key] = append(idx.byKey[key], observerId)
+	}
+
+	idx.byObserver[observerId] = entry
+}
+
+func (idx *observerIndex) remove(observerId EntityId) {
+	entry, ok := idx.byObserver[observerId]
+	if !ok {
+		return
+	}
+
+	delete(idx.byObserver, observerId)
+
+	for _, key := range entry.Keys {
+		idx.byKey[key] = removeEntityId(idx.byKey[key], observerId)
+		if len(idx.byKey[key]) == 0 {
+			delete(idx.byKey, key)
+		}
+
+		if key.Target == NoEntityId {
+			continue
+		}
+
+		idx.byTarget[key.Target] = removeEntityId(idx.byTarget[key.Target], observerId)
+		if len(idx.byTarget[key.Target]) == 0 {
+			delete(idx.byTarget, key.Target)
+		}
+	}
+}
+
+// observersOf returns the observers of the given event type and target.
+// The returned slice must not be modified.
+func (idx *observerIndex) observersOf(eventType reflect.Type, target EntityId) []EntityId {
+	return idx.byKey[observerKey{EventType: eventType, Target: target}]
+}
+
+func removeEntityId(entityIds []EntityId, entityId EntityId) []EntityId {
+	// clone, the slice might currently be iterated
+	return slices.DeleteFunc(slices.Clone(entityIds), func(id EntityId) bool { return id == entityId })
+}
+
+// unwatchDespawnedEntity removes the despawned entity from all observers watching it.
+// Observers that do not watch any other entity are despawned.
+func (w *World) unwatchDespawnedEntity(entityId EntityId) {
+	for _, observerId := range slices.Clone(w.observers.byTarget[entityId]) {
+		entity, ok := w.storage.Get(observerId)
+		if !ok {
+			continue
+		}
+
+		// do not modify the observer in place, insert an updated copy
+		// so that hooks and change detection see the change
+		observer := *entity.Get(observerComponentType).(*Observer)
+		observer.entities = removeEntityId(observer.entities, entityId)
+
+		if len(observer.entities) == 0 {
+			w.Despawn(observerId)
+			continue
+		}
+
+		w.insertComponents(observerId, []ErasedComponent{&observer})
+	}
+}
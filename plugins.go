@@ -0,0 +1,230 @@
+package byke
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// NamedPlugin is a Plugin with a name and a lifecycle. It is added to an App
+// using App.AddNamedPlugin. A NamedPlugin can only be added once to an App.
+// Only NamedPlugins are checked for duplicates, a plain Plugin added
+// using App.AddPlugin is not tracked and can be added multiple times.
+//
+// Build is called when the plugin is added. Ready, Finish and Cleanup are called
+// once all plugins were added, directly before the App starts running:
+//
+//   - Ready is polled until it returns true, e.g. to wait for async initialization.
+//   - Finish is called after all plugins are ready. At this point, all resources
+//     inserted by other plugins are available.
+//   - Cleanup is called after Finish was called on all plugins.
+//
+// Embed PluginDefaults to only implement the phases you need.
+type NamedPlugin interface {
+	Name() string
+	Build(app *App)
+	Ready(app *App) bool
+	Finish(app *App)
+	Cleanup(app *App)
+}
+
+// PluginDefaults provides default implementations of the optional
+// phases of a NamedPlugin.
+type PluginDefaults struct{}
+
+func (PluginDefaults) Ready(*App) bool { return true }
+func (PluginDefaults) Finish(*App)     {}
+func (PluginDefaults) Cleanup(*App)    {}
+
+type funcPlugin struct {
+	PluginDefaults
+	name  string
+	build Plugin
+}
+
+func (p funcPlugin) Name() string {
+	return p.name
+}
+
+func (p funcPlugin) Build(app *App) {
+	p.build(app)
+}
+
+// NewNamedPlugin wraps a Plugin function into a NamedPlugin with the given name.
+func NewNamedPlugin(name string, build Plugin) NamedPlugin {
+	return funcPlugin{name: name, build: build}
+}
+
+// AddNamedPlugin adds the given NamedPlugin to this app and calls its Build method.
+// Adding a plugin with the same name twice panics.
+func (a *App) AddNamedPlugin(plugin NamedPlugin) {
+	name := plugin.Name()
+
+	if a.pluginsFinished {
+		panic(fmt.Errorf("cannot add plugin %q, plugins are already finished", name))
+	}
+
+	if a.HasPlugin(name) {
+		panic(fmt.Errorf("plugin %q was already added", name))
+	}
+
+	a.plugins = append(a.plugins, plugin)
+	plugin.Build(a)
+}
+
+// AddPluginGroup adds all enabled plugins of the group in order.
+func (a *App) AddPluginGroup(group *PluginGroup) {
+	for _, plugin := range group.Plugins() {
+		a.AddNamedPlugin(plugin)
+	}
+}
+
+// HasPlugin returns true, if a NamedPlugin with the given name was added.
+func (a *App) HasPlugin(name string) bool {
+	return slices.ContainsFunc(a.plugins, func(plugin NamedPlugin) bool {
+		return plugin.Name() == name
+	})
+}
+
+// DefaultPluginsReadyTimeout is the time FinishPlugins waits for plugins to become
+// ready, if no other timeout was configured using App.SetPluginsReadyTimeout.
+const DefaultPluginsReadyTimeout = 30 * time.Second
+
+// SetPluginsReadyTimeout sets the time FinishPlugins waits for all plugins to become ready.
+func (a *App) SetPluginsReadyTimeout(timeout time.Duration) {
+	a.pluginsReadyTimeout = timeout
+}
+
+// FinishPlugins runs the Ready, Finish and Cleanup phases of all plugins
+// added using AddNamedPlugin. It is called by Run and only has an effect
+// the first time it is called.
+//
+// Ready is polled with a small delay between calls. If a plugin does not
+// become ready within the configured timeout, an error is returned and
+// the plugins are not finished.
+func (a *App) FinishPlugins() error {
+	if a.pluginsFinished {
+		return nil
+	}
+
+	timeout := a.pluginsReadyTimeout
+	if timeout <= 0 {
+		timeout = DefaultPluginsReadyTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for _, plugin := range a.plugins {
+		for !plugin.Ready(a) {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("plugin %q not ready after %s", plugin.Name(), timeout)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, plugin := range a.plugins {
+		plugin.Finish(a)
+	}
+
+	for _, plugin := range a.plugins {
+		plugin.Cleanup(a)
+	}
+
+	a.pluginsFinished = true
+
+	return nil
+}
+
+// PluginGroup is an ordered collection of plugins that are added together.
+// Individual members can be disabled or replaced before adding the group.
+type PluginGroup struct {
+	plugins  []NamedPlugin
+	disabled []string
+}
+
+// NewPluginGroup creates a new PluginGroup containing the given plugins.
+func NewPluginGroup(plugins ...NamedPlugin) *PluginGroup {
+	group := &PluginGroup{}
+
+	for _, plugin := range plugins {
+		group.Add(plugin)
+	}
+
+	return group
+}
+
+// Add adds a plugin at the end of the group.
+func (g *PluginGroup) Add(plugin NamedPlugin) *PluginGroup {
+	if g.indexOf(plugin.Name()) >= 0 {
+		panic(fmt.Errorf("plugin %q is already part of the group", plugin.Name()))
+	}
+
+	g.plugins = append(g.plugins, plugin)
+	return g
+}
+
+// Disable disables the plugin with the given name. It will not be added to an App.
+func (g *PluginGroup) Disable(name string) *PluginGroup {
+	g.mustIndexOf(name)
+
+	if !slices.Contains(g.disabled, name) {
+		g.disabled = append(g.disabled, name)
+	}
+
+	return g
+}
+
+// Enable enables a plugin previously disabled using Disable.
+func (g *PluginGroup) Enable(name string) *PluginGroup {
+	g.mustIndexOf(name)
+
+	g.disabled = slices.DeleteFunc(g.disabled, func(disabled string) bool { return disabled == name })
+	return g
+}
+
+// Replace replaces the plugin with the given name. The replacement keeps
+// the position of the original plugin within the group.
+func (g *PluginGroup) Replace(name string, plugin NamedPlugin) *PluginGroup {
+	idx := g.mustIndexOf(name)
+
+	if other := g.indexOf(plugin.Name()); other >= 0 && other != idx {
+		panic(fmt.Errorf("plugin %q is already part of the group", plugin.Name()))
+	}
+
+	g.plugins[idx] = plugin
+
+	// the replacement is enabled, even if the original plugin was not
+	g.disabled = slices.DeleteFunc(g.disabled, func(disabled string) bool { return disabled == name })
+
+	return g
+}
+
+// Plugins returns the enabled plugins of the group in order.
+func (g *PluginGroup) Plugins() []NamedPlugin {
+	var plugins []NamedPlugin
+
+	for _, plugin := range g.plugins {
+		if !slices.Contains(g.disabled, plugin.Name()) {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	return plugins
+}
+
+func (g *PluginGroup) indexOf(name string) int {
+	return slices.IndexFunc(g.plugins, func(plugin NamedPlugin) bool {
+		return plugin.Name() == name
+	})
+}
+
+func (g *PluginGroup) mustIndexOf(name string) int {
+	idx := g.indexOf(name)
+	if idx < 0 {
+		panic(fmt.Errorf("plugin %q is not part of the group", name))
+	}
+
+	return idx
+}
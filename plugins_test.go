@@ -0,0 +1,116 @@
+package byke
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ScoreConfig struct {
+	Multiplier int
+}
+
+type Score struct {
+	Multiplier int
+}
+
+type scorePlugin struct {
+	PluginDefaults
+	phases *[]string
+}
+
+func (scorePlugin) Name() string {
+	return "Score"
+}
+
+func (p scorePlugin) Build(*App) {
+	*p.phases = append(*p.phases, "build")
+}
+
+func (p scorePlugin) Finish(app *App) {
+	*p.phases = append(*p.phases, "finish")
+
+	// the config might have been inserted by a plugin added later
+	config, ok := app.World().ResourceOf[ScoreConfig]()
+	if !ok {
+		config = &ScoreConfig{Multiplier: 1}
+	}
+
+	app.InsertResource(Score{Multiplier: config.Multiplier})
+}
+
+func (p scorePlugin) Cleanup(*App) {
+	*p.phases = append(*p.phases, "cleanup")
+}
+
+func TestNamedPlugin(t *testing.T) {
+	var phases []string
+
+	var app App
+	app.AddNamedPlugin(scorePlugin{phases: &phases})
+	app.AddNamedPlugin(NewNamedPlugin("ScoreConfig", func(app *App) {
+		app.InsertResource(ScoreConfig{Multiplier: 3})
+	}))
+
+	require.True(t, app.HasPlugin("Score"))
+	require.Panics(t, func() { app.AddNamedPlugin(scorePlugin{phases: &phases}) })
+
+	require.NoError(t, app.FinishPlugins())
+	require.NoError(t, app.FinishPlugins())
+
+	require.Equal(t, []string{"build", "finish", "cleanup"}, phases)
+	require.Equal(t, 3, app.World().RequireResourceOf[Score]().Multiplier)
+
+	require.Panics(t, func() { app.AddNamedPlugin(NewNamedPlugin("Late", func(*App) {})) })
+}
+
+func TestPluginGroup(t *testing.T) {
+	var added []string
+
+	makePlugin := func(name string) NamedPlugin {
+		return NewNamedPlugin(name, func(*App) { added = append(added, name) })
+	}
+
+	group := NewPluginGroup(makePlugin("A"), makePlugin("B"), makePlugin("C")).
+		Disable("A").
+		Replace("B", makePlugin("B2"))
+
+	require.Panics(t, func() { group.Disable("Unknown") })
+	require.Panics(t, func() { group.Add(makePlugin("C")) })
+
+	var app App
+	app.AddPluginGroup(group)
+
+	require.Equal(t, []string{"B2", "C"}, added)
+	require.False(t, app.HasPlugin("A"))
+}
+
+type slowPlugin struct {
+	PluginDefaults
+	ready bool
+}
+
+func (*slowPlugin) Name() string {
+	return "Slow"
+}
+
+func (*slowPlugin) Build(*App) {}
+
+func (p *slowPlugin) Ready(*App) bool {
+	return p.ready
+}
+
+func TestFinishPluginsTimeout(t *testing.T) {
+	plugin := &slowPlugin{}
+
+	var app App
+	app.AddNamedPlugin(plugin)
+	app.SetPluginsReadyTimeout(20 * time.Millisecond)
+
+	require.Error(t, app.FinishPlugins())
+
+	// finishing can be retried once the plugin is ready
+	plugin.ready = true
+	require.NoError(t, app.FinishPlugins())
+}
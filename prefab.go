@@ -0,0 +1,307 @@
+package byke
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+var _ = ValidateComponent[PrefabInstance]()
+
+// Prefab is a template for a hierarchy of entities. Register it by name using
+// Prefabs.Register and spawn it using Commands.SpawnPrefab.
+type Prefab struct {
+	Components []ErasedComponent
+	Children   []*Prefab
+}
+
+// NewPrefab creates a new Prefab with the given components. Bundles are flattened,
+// use Prefab.WithChildren to add children to the prefab.
+func NewPrefab(components ...ErasedComponent) *Prefab {
+	return &Prefab{Components: flattenComponents(nil, components...)}
+}
+
+// WithChildren adds children to the prefab.
+func (p *Prefab) WithChildren(children ...*Prefab) *Prefab {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+// nodeAt returns the node of the prefab at the given path of child indices.
+func (p *Prefab) nodeAt(path []int) (*Prefab, bool) {
+	node := p
+
+	for _, idx := range path {
+		if idx >= len(node.Children) {
+			return nil, false
+		}
+
+		node = node.Children[idx]
+	}
+
+	return node, true
+}
+
+// prefabFile is the json representation of a Prefab.
+type prefabFile struct {
+	// component values keyed by the name of the component type
+	Components map[string]json.RawMessage `json:"components"`
+	Children   []prefabFile               `json:"children"`
+}
+
+// DecodePrefab decodes a Prefab from json. Components are referenced by the name
+// of their type and must be known to the TypeRegistry, see TypeRegistry.RegisterComponent.
+// DecodePrefab does not access a World and can be called from any goroutine:
+//
+//	{
+//	  "components": {"byke.Name": {"Name": "Player"}},
+//	  "children": [{"components": {"main.Weapon": {"Damage": 10}}}]
+//	}
+func DecodePrefab(data []byte) (*Prefab, error) {
+	var file prefabFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode prefab: %w", err)
+	}
+
+	return file.toPrefab()
+}
+
+func (f *prefabFile) toPrefab() (*Prefab, error) {
+	prefab := &Prefab{}
+
+	for name, data := range f.Components {
+		componentType, ok := componentTypeByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown component type %q", name)
+		}
+
+		component := componentType.New()
+		if err := json.Unmarshal(data, component); err != nil {
+			return nil, fmt.Errorf("decode component %s: %w", name, err)
+		}
+
+		prefab.Components = append(prefab.Components, component)
+	}
+
+	// the order of the components should not depend on map iteration
+	slices.SortFunc(prefab.Components, func(a, b ErasedComponent) int {
+		return int(a.ComponentType().Id) - int(b.ComponentType().Id)
+	})
+
+	for idx := range f.Children {
+		child, err := f.Children[idx].toPrefab()
+		if err != nil {
+			return nil, err
+		}
+
+		prefab.Children = append(prefab.Children, child)
+	}
+
+	return prefab, nil
+}
+
+// PrefabHandle references a Prefab registered in Prefabs.
+type PrefabHandle string
+
+type prefabEntry struct {
+	Prefab  *Prefab
+	Version int
+}
+
+// Prefabs is a resource holding all prefabs by name.
+type Prefabs struct {
+	prefabs map[PrefabHandle]prefabEntry
+
+	// incremented each time a prefab is registered
+	version int
+}
+
+// Register registers a prefab by its name. Registering a prefab with a name that is already
+// in use replaces the previous prefab. Spawned instances of the previous prefab are updated:
+// Each component taken from the prefab is replaced by a copy of the new prefabs component,
+// even if it was modified at runtime. Only components overridden in SpawnPrefab are kept.
+func (p *Prefabs) Register(name string, prefab *Prefab) PrefabHandle {
+	if p.prefabs == nil {
+		p.prefabs = map[PrefabHandle]prefabEntry{}
+	}
+
+	handle := PrefabHandle(name)
+
+	p.version += 1
+	p.prefabs[handle] = prefabEntry{
+		Prefab:  prefab,
+		Version: p.prefabs[handle].Version + 1,
+	}
+
+	return handle
+}
+
+// Get returns the prefab for the given handle.
+func (p *Prefabs) Get(handle PrefabHandle) (*Prefab, bool) {
+	entry, ok := p.prefabs[handle]
+	return entry.Prefab, ok
+}
+
+// PrefabInstance links an entity spawned from a Prefab to the node of the
+// prefab it was created from.
+type PrefabInstance struct {
+	Component[PrefabInstance]
+	Handle PrefabHandle
+
+	// path of child indices from the root of the prefab to the node
+	path    []int
+	version int
+
+	// component types taken from the prefab
+	types []*ComponentType
+
+	// component types overridden when spawning the prefab
+	overridden []*ComponentType
+}
+
+// SpawnPrefab spawns a new instance of the prefab. The overrides are inserted into the root
+// entity instead of the prefabs components of the same type. Overridden components are
+// not updated if the prefab is registered again, all other components of the instance
+// are replaced, see Prefabs.Register.
+func (c *Commands) SpawnPrefab(handle PrefabHandle, overrides ...ErasedComponent) EntityCommands {
+	entityId := c.world.reserveEntityId()
+
+	c.Add(&spawnPrefabCommand{
+		EntityId:  entityId,
+		Handle:    handle,
+		Overrides: overrides,
+	})
+
+	return EntityCommands{
+		entityId: entityId,
+		commands: c,
+	}
+}
+
+type spawnPrefabCommand struct {
+	EntityId  EntityId
+	Handle    PrefabHandle
+	Overrides []ErasedComponent
+}
+
+func (c *spawnPrefabCommand) Apply(world *World) {
+	var entry prefabEntry
+
+	prefabs, ok := world.ResourceOf[Prefabs]()
+	if ok {
+		entry, ok = prefabs.prefabs[c.Handle]
+	}
+
+	if !ok {
+		slog.Warn("Can not spawn unknown prefab", slog.String("prefab", string(c.Handle)))
+		return
+	}
+
+	overrides := flattenComponents(nil, c.Overrides...)
+
+	var overridden []*ComponentType
+	for _, component := range overrides {
+		overridden = append(overridden, component.ComponentType())
+	}
+
+	components := prefabComponentsOf(c.Handle, entry, entry.Prefab, nil, overridden)
+
+	// overrides come first, the first component of a type wins
+	world.spawnWithEntityId(c.EntityId, append(overrides, components...))
+}
+
+// prefabComponentsOf returns the components to spawn for the given node,
+// including SpawnChild components for all children of the node.
+func prefabComponentsOf(handle PrefabHandle, entry prefabEntry, node *Prefab, path []int, overridden []*ComponentType) []ErasedComponent {
+	instance := &PrefabInstance{
+		Handle:     handle,
+		path:       path,
+		version:    entry.Version,
+		overridden: overridden,
+	}
+
+	components := []ErasedComponent{instance}
+
+	for _, component := range node.Components {
+		componentType := component.ComponentType()
+
+		instance.types = append(instance.types, componentType)
+
+		if !slices.Contains(overridden, componentType) {
+			// each instance gets its own copy of the component
+			components = append(components, componentType.CopyOf(component))
+		}
+	}
+
+	for idx, child := range node.Children {
+		childPath := append(slices.Clip(path), idx)
+		components = append(components, SpawnChild(prefabComponentsOf(handle, entry, child, childPath, nil)...))
+	}
+
+	return components
+}
+
+// updatePrefabInstancesSystem updates the components of prefab instances
+// after their prefab was registered again.
+func updatePrefabInstancesSystem(
+	commands *Commands,
+	prefabs *Prefabs,
+	lastVersion *Local[int],
+	query Query[struct {
+		EntityId
+		Instance *PrefabInstance
+	}],
+) {
+	// no need to look at the instances if no prefab was registered since the last run
+	if lastVersion.Value == prefabs.version {
+		return
+	}
+
+	lastVersion.Value = prefabs.version
+
+	for item := range query.Items() {
+		instance := item.Instance
+
+		entry, ok := prefabs.prefabs[instance.Handle]
+		if !ok || entry.Version == instance.version {
+			continue
+		}
+
+		instance.version = entry.Version
+
+		// changes to the structure of the prefab are not applied
+		node, ok := entry.Prefab.nodeAt(instance.path)
+		if !ok {
+			continue
+		}
+
+		previousTypes := instance.types
+		instance.types = nil
+
+		var components []ErasedComponent
+
+		for _, component := range node.Components {
+			componentType := component.ComponentType()
+			instance.types = append(instance.types, componentType)
+
+			if !slices.Contains(instance.overridden, componentType) {
+				components = append(components, componentType.CopyOf(component))
+			}
+		}
+
+		commands.Entity(item.EntityId).Insert(components...)
+
+		// remove components that are not part of the prefab anymore
+		for _, componentType := range previousTypes {
+			if slices.Contains(instance.types, componentType) || slices.Contains(instance.overridden, componentType) {
+				continue
+			}
+
+			commands.Add(&applyEntityCommands{
+				EntityId: item.EntityId,
+				Commands: []EntityCommand{(*removeComponentEntityCommand)(componentType)},
+			})
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package byke
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type PrefabHealth struct {
+	Component[PrefabHealth]
+	Value int
+}
+
+type PrefabArmor struct {
+	Component[PrefabArmor]
+	Value int
+}
+
+func TestPrefab(t *testing.T) {
+	var app App
+
+	w := app.World()
+	w.TypeRegistry().RegisterComponent[PrefabHealth]()
+
+	prefab, err := DecodePrefab([]byte(`{
+		"components": {"byke.PrefabHealth": {"Value": 10}},
+		"children": [{"components": {"byke.Name": {"Name": "Weapon"}}}]
+	}`))
+	require.NoError(t, err)
+
+	prefabs := w.RequireResourceOf[Prefabs]()
+	handle := prefabs.Register("player", prefab.WithChildren(NewPrefab(Named("Shield"))))
+
+	var defaultId, overriddenId EntityId
+
+	w.RunSystem(func(commands *Commands) {
+		defaultId = commands.SpawnPrefab(handle).Id()
+		overriddenId = commands.SpawnPrefab(handle, PrefabHealth{Value: 99}).Id()
+	})
+
+	healthOf := func(entityId EntityId) int {
+		entity, ok := w.Entity(entityId)
+		require.True(t, ok)
+		return entity.Get(PrefabHealth{}.ComponentType()).(*PrefabHealth).Value
+	}
+
+	require.Equal(t, 10, healthOf(defaultId))
+	require.Equal(t, 99, healthOf(overriddenId))
+	require.Len(t, w.Hierarchy().Children(defaultId), 2)
+
+	// nothing to update until the prefab is registered again
+	entity, _ := w.Entity(defaultId)
+	entity.Get(PrefabHealth{}.ComponentType()).(*PrefabHealth).Value = 15
+	w.RunSchedule(PreUpdate)
+	require.Equal(t, 15, healthOf(defaultId))
+
+	// hot reload the prefab with a different health and an additional component
+	prefabs.Register("player", NewPrefab(PrefabHealth{Value: 20}, PrefabArmor{Value: 5}))
+	w.RunSchedule(PreUpdate)
+
+	// runtime changes to components of the prefab are replaced
+	require.Equal(t, 20, healthOf(defaultId))
+	require.Equal(t, 99, healthOf(overriddenId))
+
+	entity, _ = w.Entity(overriddenId)
+	require.True(t, entity.Has(PrefabArmor{}.ComponentType()))
+
+	// children are kept, the structure of the prefab is not updated
+	require.Len(t, w.Hierarchy().Children(defaultId), 2)
+}
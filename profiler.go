@@ -0,0 +1,307 @@
+package byke
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oliverbestmann/puffin-go"
+)
+
+// sharedProfiler receives the scopes started using NewProfileScope. Those scopes
+// have no access to a World, e.g. the scopes on byke2d's render thread, so they
+// are recorded into the Profiler that was most recently added via PluginProfiler.
+// Scopes started by a World are always recorded into the World's own Profiler.
+var sharedProfiler atomic.Pointer[Profiler]
+
+// ProfileScope measures the time between its creation and a call to End.
+// The scope is reported to puffin and to a Profiler, if any.
+type ProfileScope struct {
+	puffin puffin.Scope
+
+	profiler *Profiler
+	name     string
+	value    string
+	start    time.Time
+}
+
+// NewProfileScope starts a new ProfileScope. Use it like this:
+//
+//	defer byke.NewProfileScope("Physics").End()
+//
+// As the scope is not tied to a World, it is recorded into the Profiler
+// most recently added using PluginProfiler.
+func NewProfileScope(name string) ProfileScope {
+	return NewProfileScopeWithValue(name, "")
+}
+
+// NewProfileScopeWithValue starts a new ProfileScope with an additional value,
+// e.g. the name of the system being run.
+func NewProfileScopeWithValue(name, value string) ProfileScope {
+	return newProfileScope(sharedProfiler.Load(), name, value)
+}
+
+func newProfileScope(profiler *Profiler, name, value string) ProfileScope {
+	scope := ProfileScope{
+		profiler: profiler,
+		name:     name,
+		value:    value,
+	}
+
+	if value != "" {
+		scope.puffin = puffin.NewScopeWithValue(name, value)
+	} else {
+		scope.puffin = puffin.NewScope(name)
+	}
+
+	if scope.profiler != nil {
+		scope.start = time.Now()
+	}
+
+	return scope
+}
+
+// End ends the scope.
+func (s ProfileScope) End() {
+	s.puffin.End()
+
+	if s.profiler != nil {
+		s.profiler.record(traceEvent{
+			Name:     s.name,
+			Value:    s.value,
+			Start:    s.start,
+			Duration: time.Since(s.start),
+		})
+	}
+}
+
+type traceEvent struct {
+	Name     string
+	Value    string
+	Start    time.Time
+	Duration time.Duration
+}
+
+type traceFrame struct {
+	Start  time.Time
+	Events []traceEvent
+}
+
+// ProfilerConfig configures PluginProfiler.
+type ProfilerConfig struct {
+	// Frames is the number of frames kept by the profiler. Defaults to 300.
+	Frames int
+
+	// Directory to write traces to using Profiler.Dump. Defaults to the working directory.
+	Directory string
+
+	// MaxEventsPerFrame limits the number of scopes recorded into a single frame. If the
+	// limit is reached, e.g. because no Main schedule runs, a new frame is started.
+	// Defaults to 100000.
+	MaxEventsPerFrame int
+}
+
+// Profiler records ProfileScope measurements of the most recent frames. The frames
+// can be written as Chrome Trace Event JSON, which can be opened in
+// https://ui.perfetto.dev or chrome://tracing.
+//
+// Scopes ended on other goroutines are recorded too, but all scopes are
+// reported as running on the same thread.
+type Profiler struct {
+	config ProfilerConfig
+
+	mu sync.Mutex
+
+	// ring buffer of the recorded frames, next is the index to write the next frame to
+	frames []traceFrame
+	next   int
+
+	current traceFrame
+}
+
+// PluginProfiler enables a Profiler for the apps World. Use World.Profiler to dump
+// the recorded frames.
+func PluginProfiler(config ProfilerConfig) Plugin {
+	return func(app *App) {
+		profiler := NewProfiler(config)
+
+		app.World().profiler = profiler
+		sharedProfiler.Store(profiler)
+	}
+}
+
+// NewProfiler creates a new Profiler.
+func NewProfiler(config ProfilerConfig) *Profiler {
+	if config.Frames <= 0 {
+		config.Frames = 300
+	}
+
+	if config.MaxEventsPerFrame <= 0 {
+		config.MaxEventsPerFrame = 100_000
+	}
+
+	profiler := &Profiler{
+		config:  config,
+		current: traceFrame{Start: time.Now()},
+	}
+
+	return profiler
+}
+
+// Profiler returns the Profiler of this world, or nil, if profiling is not enabled.
+func (w *World) Profiler() *Profiler {
+	return w.profiler
+}
+
+// newProfileScope starts a new ProfileScope that is recorded into the worlds Profiler.
+func (w *World) newProfileScope(name, value string) ProfileScope {
+	return newProfileScope(w.profiler, name, value)
+}
+
+func (p *Profiler) record(event traceEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current.Events = append(p.current.Events, event)
+
+	if len(p.current.Events) >= p.config.MaxEventsPerFrame {
+		p.finishFrameLocked()
+	}
+}
+
+// beginFrame finishes the current frame and starts a new one.
+func (p *Profiler) beginFrame() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finishFrameLocked()
+}
+
+func (p *Profiler) finishFrameLocked() {
+	if len(p.current.Events) > 0 {
+		if len(p.frames) < p.config.Frames {
+			p.frames = append(p.frames, p.current)
+		} else {
+			// overwrite the oldest frame
+			p.frames[p.next] = p.current
+		}
+
+		p.next = (p.next + 1) % p.config.Frames
+	}
+
+	p.current = traceFrame{Start: time.Now()}
+}
+
+// orderedFramesLocked returns a copy of the recorded frames, oldest frame first.
+func (p *Profiler) orderedFramesLocked() []traceFrame {
+	if len(p.frames) < p.config.Frames {
+		return append([]traceFrame(nil), p.frames...)
+	}
+
+	frames := make([]traceFrame, 0, len(p.frames))
+	frames = append(frames, p.frames[p.next:]...)
+	frames = append(frames, p.frames[:p.next]...)
+
+	return frames
+}
+
+// chromeTraceEvent is a single event in the Chrome Trace Event format.
+type chromeTraceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp float64           `json:"ts"`
+	Duration  float64           `json:"dur,omitempty"`
+	Pid       int               `json:"pid"`
+	Tid       int               `json:"tid"`
+	Scope     string            `json:"s,omitempty"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the last n recorded frames as Chrome Trace Event JSON.
+// If n is zero or negative, all recorded frames are written.
+func (p *Profiler) WriteChromeTrace(w io.Writer, n int) error {
+	// copy the frames, so we do not need to hold the lock while writing
+	p.mu.Lock()
+	frames := p.orderedFramesLocked()
+	p.mu.Unlock()
+
+	if n > 0 && n < len(frames) {
+		frames = frames[len(frames)-n:]
+	}
+
+	var events []chromeTraceEvent
+	if len(frames) == 0 {
+		events = []chromeTraceEvent{}
+	}
+
+	var epoch time.Time
+	if len(frames) > 0 {
+		epoch = frames[0].Start
+	}
+
+	micros := func(d time.Duration) float64 {
+		return float64(d) / float64(time.Microsecond)
+	}
+
+	for idx, frame := range frames {
+		events = append(events, chromeTraceEvent{
+			Name:      fmt.Sprintf("Frame %d", idx),
+			Category:  "frame",
+			Phase:     "i",
+			Timestamp: micros(frame.Start.Sub(epoch)),
+			Pid:       1,
+			Tid:       1,
+			Scope:     "g",
+		})
+
+		for _, event := range frame.Events {
+			chromeEvent := chromeTraceEvent{
+				Name:      event.Name,
+				Category:  "scope",
+				Phase:     "X",
+				Timestamp: micros(event.Start.Sub(epoch)),
+				Duration:  micros(event.Duration),
+				Pid:       1,
+				Tid:       1,
+			}
+
+			if event.Value != "" {
+				chromeEvent.Name = event.Name + " " + event.Value
+				chromeEvent.Args = map[string]string{"value": event.Value}
+			}
+
+			events = append(events, chromeEvent)
+		}
+	}
+
+	return json.NewEncoder(w).Encode(map[string]any{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
+
+// Dump writes all recorded frames to a new file in the configured
+// directory and returns the path of the file.
+func (p *Profiler) Dump() (string, error) {
+	name := fmt.Sprintf("trace-%s.json", time.Now().Format("20060102-150405"))
+	path := filepath.Join(p.config.Directory, name)
+
+	fp, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create trace file: %w", err)
+	}
+
+	defer fp.Close()
+
+	if err := p.WriteChromeTrace(fp, 0); err != nil {
+		return "", fmt.Errorf("write trace: %w", err)
+	}
+
+	return path, fp.Close()
+}
@@ -0,0 +1,95 @@
+package byke
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	var app App
+	app.AddPlugin(PluginProfiler(ProfilerConfig{Frames: 2, Directory: t.TempDir()}))
+	defer sharedProfiler.Store(nil)
+
+	app.AddSystems(Update, func(commands *Commands) {
+		commands.Spawn(Named("Test"))
+	})
+
+	w := app.World()
+	for range 4 {
+		w.RunSchedule(Main)
+	}
+
+	// finish the last frame
+	profiler := w.Profiler()
+	profiler.beginFrame()
+
+	var output bytes.Buffer
+	require.NoError(t, profiler.WriteChromeTrace(&output, 0))
+
+	var trace struct {
+		TraceEvents []chromeTraceEvent `json:"traceEvents"`
+	}
+
+	require.NoError(t, json.Unmarshal(output.Bytes(), &trace))
+
+	names := map[string]int{}
+	for _, event := range trace.TraceEvents {
+		names[event.Name] += 1
+	}
+
+	// only the last two frames are kept
+	require.Equal(t, 2, names["Frame 0"]+names["Frame 1"])
+	require.Equal(t, 2, names["RunSchedule Update"])
+	require.Positive(t, names["byke.FlushCommands"])
+
+	path, err := profiler.Dump()
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, output.String(), string(content))
+}
+
+func TestProfilerRingBuffer(t *testing.T) {
+	profiler := NewProfiler(ProfilerConfig{Frames: 3, MaxEventsPerFrame: 2})
+
+	for idx := range 10 {
+		profiler.record(traceEvent{Name: "Scope", Value: strconv.Itoa(idx)})
+	}
+
+	// events are split into frames of two events, only the last three frames are kept
+	frames := profiler.orderedFramesLocked()
+	require.Len(t, frames, 3)
+
+	var values []string
+	for _, frame := range frames {
+		for _, event := range frame.Events {
+			values = append(values, event.Value)
+		}
+	}
+
+	require.Equal(t, []string{"4", "5", "6", "7", "8", "9"}, values)
+	require.Empty(t, profiler.current.Events)
+}
+
+func TestProfilerIsPerWorld(t *testing.T) {
+	var profiled App
+	profiled.AddPlugin(PluginProfiler(ProfilerConfig{}))
+	defer sharedProfiler.Store(nil)
+
+	var other App
+	other.AddSystems(Update, func() {})
+	other.World().RunSchedule(Main)
+
+	require.Nil(t, other.World().Profiler())
+
+	// scopes of the other world are not recorded into the profiler
+	profiler := profiled.World().Profiler()
+	profiler.beginFrame()
+	require.Empty(t, profiler.orderedFramesLocked())
+}
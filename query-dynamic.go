@@ -0,0 +1,48 @@
+package byke
+
+import (
+	"iter"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// DynamicQuery matches all entities having a set of components only known at
+// runtime, e.g. in a scripting integration. Like a Query, a DynamicQuery does
+// not match disabled entities.
+type DynamicQuery struct {
+	world *World
+	query *spoke.CachedQuery
+}
+
+// DynamicQuery creates a new DynamicQuery matching all entities with all the given components.
+// Keep the returned value around to not rebuild the query each time you use it.
+func (w *World) DynamicQuery(componentTypes ...*ComponentType) *DynamicQuery {
+	var builder spoke.QueryBuilder
+
+	for _, componentType := range componentTypes {
+		builder.FetchComponent(componentType, false)
+	}
+
+	return &DynamicQuery{
+		world: w,
+		query: w.storage.OptimizeQuery(builder.Build()),
+	}
+}
+
+// Entities yields all entities matched by the query.
+func (q *DynamicQuery) Entities() iter.Seq[EntityRef] {
+	return func(yield func(EntityRef) bool) {
+		it := q.world.storage.IterQuery(q.query, spoke.QueryContext{})
+
+		for {
+			entity, more := it.Next()
+			if !more {
+				return
+			}
+
+			if !yield(entity) {
+				return
+			}
+		}
+	}
+}
@@ -68,10 +68,45 @@ func (q *Query[T]) Get(entityId EntityId) (T, bool) {
 		return tZero, false
 	}
 
-	target := query.FromEntity[T](q.inner.Setters, ref)
+	target := query.FromEntity[T](q.inner.Setters, q.inner.QueryContext, ref)
 	return target, true
 }
 
+// RelatedTo yields the items of all entities that are related to the target entity
+// using the relationship component R, in the order they were added to the target.
+// E.g. q.RelatedTo[ChildOf](parentId) yields the items of all children of parentId.
+// Entities not matching this query are skipped.
+func (q *Query[T]) RelatedTo[R IsComponent[R]](targetId EntityId) iter.Seq[T] {
+	targetType, _, ok := relationshipTargetsOf(spoke.ComponentTypeOf[R]().New())
+	if !ok {
+		var rZero R
+		panic(fmt.Sprintf("type %T is not a relationship component", rZero))
+	}
+
+	return func(yield func(T) bool) {
+		target, ok := q.inner.Storage.Get(targetId)
+		if !ok {
+			return
+		}
+
+		relationshipTarget, ok := target.Get(targetType).(isRelationshipTargetType)
+		if !ok {
+			return
+		}
+
+		for _, sourceId := range relationshipTarget.Children() {
+			item, ok := q.Get(sourceId)
+			if !ok {
+				continue
+			}
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
 func (q *Query[T]) Count() int {
 	it := q.inner.Storage.IterQuery(q.inner.Query, q.inner.QueryContext)
 
@@ -139,6 +174,7 @@ type queryParamState struct {
 
 func (q *queryParamState) GetValue(sc SystemContext) (reflect.Value, error) {
 	q.inner.QueryContext.LastRun = sc.LastRun
+	q.inner.QueryContext.ThisRun = q.world.currentTick
 	return q.ptrToValue.Elem(), nil
 }
 
@@ -167,7 +203,7 @@ func iterValues[T any](inner *innerQuery, fn func(value T) bool) {
 			break
 		}
 
-		target := query.FromEntity[T](inner.Setters, ref)
+		target := query.FromEntity[T](inner.Setters, inner.QueryContext, ref)
 
 		if !fn(target) {
 			break
@@ -19,6 +19,7 @@ type IsRelationshipTargetComponent[T IsImmutableComponent[T]] interface {
 type isRelationshipTargetType interface {
 	ErasedComponent
 	RelationshipType() *spoke.ComponentType
+	DespawnPolicy() DespawnPolicy
 	Children() []EntityId
 	addChild(id EntityId)
 	removeChild(id EntityId)
@@ -35,7 +36,42 @@ type isRelationshipComponent interface {
 	RelationshipEntityId() EntityId
 }
 
-// RelationshipTarget must be embedded on the parent side of a relationship
+type IsManyRelationshipComponent[T IsImmutableComponent[T]] interface {
+	IsImmutableComponent[T]
+	isManyRelationshipComponent
+}
+
+type isManyRelationshipComponent interface {
+	ErasedComponent
+	RelationshipTargetType() *spoke.ComponentType
+	RelationshipEntityIds() []EntityId
+}
+
+type isRemoveRelationshipTarget interface {
+	removeTarget(id EntityId)
+}
+
+// DespawnPolicy defines what happens to the entities on the source side of a
+// relationship, when the entity on the target side is despawned.
+type DespawnPolicy uint8
+
+const (
+	// DespawnCascade despawns all source entities together with the target.
+	DespawnCascade DespawnPolicy = iota
+
+	// DespawnUnlink removes the target from the relationship components of all source
+	// entities. The relationship component is removed, if it has no targets left.
+	DespawnUnlink
+
+	// DespawnKeep does not touch the source entities. Their relationship
+	// components keep pointing to the despawned target.
+	DespawnKeep
+)
+
+// RelationshipTarget must be embedded on the parent side of a relationship.
+// When the entity holding the RelationshipTarget is despawned, all entities
+// related to it are despawned too. Implement a DespawnPolicy method on the
+// component to change this behaviour.
 type RelationshipTarget[Child IsImmutableComponent[Child]] struct {
 	_children []EntityId
 }
@@ -45,16 +81,34 @@ func (*RelationshipTarget[Child]) RelationshipType() *spoke.ComponentType {
 }
 
 func (p *RelationshipTarget[Child]) addChild(childId EntityId) {
+	if slices.Contains(p._children, childId) {
+		return
+	}
+
 	p._children = append(p._children, childId)
 }
 
 func (p *RelationshipTarget[Child]) removeChild(childId EntityId) {
 	idx := slices.Index(p._children, childId)
 	if idx >= 0 {
-		p._children = slices.Delete(p._children, idx, idx+1)
+		// clone first, the backing array might be shared with a previous copy
+		p._children = slices.Delete(slices.Clone(p._children), idx, idx+1)
 	}
 }
 
+func (*RelationshipTarget[Child]) DespawnPolicy() DespawnPolicy {
+	return DespawnCascade
+}
+
+// moveChild moves the given child to the given index. The index is clamped to
+// the number of children. This never modifies the backing array of the
+// current children slice, as it might be shared with a previous copy.
+func (p *RelationshipTarget[Child]) moveChild(childId EntityId, index int) {
+	children := slices.DeleteFunc(slices.Clone(p._children), func(id EntityId) bool { return id == childId })
+	index = max(0, min(index, len(children)))
+	p._children = slices.Insert(children, index, childId)
+}
+
 // Children returns the children in this component.
 // You **must not** modify the returned slice.
 func (p *RelationshipTarget[Child]) Children() []EntityId {
@@ -68,6 +122,40 @@ func (Relationship[Parent]) RelationshipTargetType() *spoke.ComponentType {
 	return spoke.ComponentTypeOf[Parent]()
 }
 
+// ManyRelationship must be embedded on the client side of a relationship that can
+// target multiple entities at once. The order of the Targets is kept as is.
+type ManyRelationship[Target IsImmutableComponent[Target]] struct {
+	Targets []EntityId
+}
+
+func (ManyRelationship[Target]) RelationshipTargetType() *spoke.ComponentType {
+	return spoke.ComponentTypeOf[Target]()
+}
+
+func (r ManyRelationship[Target]) RelationshipEntityIds() []EntityId {
+	return r.Targets
+}
+
+func (r *ManyRelationship[Target]) removeTarget(targetId EntityId) {
+	// clone first, the backing array might be shared with a previous copy
+	r.Targets = slices.DeleteFunc(slices.Clone(r.Targets), func(id EntityId) bool { return id == targetId })
+}
+
+// relationshipTargetsOf returns the type of the relationship target and the
+// ids of all entities targeted by the given relationship component.
+func relationshipTargetsOf(component any) (*spoke.ComponentType, []EntityId, bool) {
+	switch component := component.(type) {
+	case isRelationshipComponent:
+		return component.RelationshipTargetType(), []EntityId{component.RelationshipEntityId()}, true
+
+	case isManyRelationshipComponent:
+		return component.RelationshipTargetType(), component.RelationshipEntityIds(), true
+
+	default:
+		return nil, nil, false
+	}
+}
+
 type ChildOf struct {
 	ImmutableComponent[ChildOf]
 	Relationship[Children]
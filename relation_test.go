@@ -0,0 +1,169 @@
+package byke
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Targets struct {
+	ImmutableComponent[Targets]
+	ManyRelationship[TargetedBy]
+}
+
+type TargetedBy struct {
+	ImmutableComponent[TargetedBy]
+	RelationshipTarget[Targets]
+}
+
+func (TargetedBy) DespawnPolicy() DespawnPolicy {
+	return DespawnUnlink
+}
+
+type InInventoryOf struct {
+	ImmutableComponent[InInventoryOf]
+	ManyRelationship[Inventory]
+}
+
+type Inventory struct {
+	ImmutableComponent[Inventory]
+	RelationshipTarget[InInventoryOf]
+}
+
+func (Inventory) DespawnPolicy() DespawnPolicy {
+	return DespawnKeep
+}
+
+type MemberOf struct {
+	ImmutableComponent[MemberOf]
+	ManyRelationship[Members]
+}
+
+type Members struct {
+	ImmutableComponent[Members]
+	RelationshipTarget[MemberOf]
+}
+
+var (
+	_ = ValidateComponent[Targets]()
+	_ = ValidateComponent[TargetedBy]()
+	_ = ValidateComponent[InInventoryOf]()
+	_ = ValidateComponent[Inventory]()
+	_ = ValidateComponent[MemberOf]()
+	_ = ValidateComponent[Members]()
+)
+
+func targetsOf(targets ...EntityId) Targets {
+	return Targets{ManyRelationship: ManyRelationship[TargetedBy]{Targets: targets}}
+}
+
+func TestManyRelationship(t *testing.T) {
+	w := NewWorld()
+
+	a := w.Spawn([]ErasedComponent{Named("A")})
+	b := w.Spawn([]ErasedComponent{Named("B")})
+
+	x := w.Spawn([]ErasedComponent{Named("X"), targetsOf(a, b)})
+	y := w.Spawn([]ErasedComponent{Named("Y"), targetsOf(a)})
+
+	namesRelatedTo := func(targetId EntityId) []string {
+		var names []string
+
+		w.RunSystem(func(q Query[Name]) {
+			for name := range q.RelatedTo[Targets](targetId) {
+				names = append(names, name.Name)
+			}
+		})
+
+		return names
+	}
+
+	require.Equal(t, []string{"X", "Y"}, namesRelatedTo(a))
+	require.Equal(t, []string{"X"}, namesRelatedTo(b))
+
+	// retarget y from a to b
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(y).Insert(targetsOf(b))
+	})
+
+	require.Equal(t, []string{"X"}, namesRelatedTo(a))
+	require.Equal(t, []string{"X", "Y"}, namesRelatedTo(b))
+
+	// unlink policy: x only targets a afterwards, y has no targets anymore
+	w.Despawn(b)
+
+	w.RunSystem(func(q Query[struct {
+		EntityId EntityId
+		Targets  Option[Targets]
+	}]) {
+		item, _ := q.Get(x)
+		require.Equal(t, []EntityId{a}, item.Targets.MustGet().Targets)
+
+		item, _ = q.Get(y)
+		_, ok := item.Targets.Get()
+		require.False(t, ok)
+	})
+}
+
+func TestManyRelationshipDespawnPolicies(t *testing.T) {
+	w := NewWorld()
+
+	inventory := w.Spawn([]ErasedComponent{Named("Inventory")})
+	sword := w.Spawn([]ErasedComponent{
+		Named("Sword"),
+		InInventoryOf{ManyRelationship: ManyRelationship[Inventory]{Targets: []EntityId{inventory}}},
+	})
+
+	// keep policy: the sword is not touched
+	w.Despawn(inventory)
+
+	w.RunSystem(func(q Query[InInventoryOf]) {
+		item, ok := q.Get(sword)
+		require.True(t, ok)
+		require.Equal(t, []EntityId{inventory}, item.Targets)
+	})
+
+	// relating to the despawned inventory again must not fail
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(sword).Insert(
+			InInventoryOf{ManyRelationship: ManyRelationship[Inventory]{Targets: []EntityId{inventory}}},
+		)
+	})
+
+	// removing the dangling relationship must not fail
+	require.NoError(t, w.TryDespawn(sword))
+
+	// default cascade policy for Children
+	parent := w.Spawn([]ErasedComponent{SpawnChild(Named("Child"))})
+	children := slices.Clone(w.Hierarchy().Children(parent))
+	require.Len(t, children, 1)
+
+	w.Despawn(parent)
+	require.ErrorIs(t, w.TryDespawn(children[0]), ErrNoSuchEntity)
+}
+
+func TestManyRelationshipCascadeDespawnsOnce(t *testing.T) {
+	w := NewWorld()
+
+	root := w.Spawn([]ErasedComponent{Named("Root")})
+	a := w.Spawn([]ErasedComponent{Named("A"), ChildOf{Parent: root}})
+	b := w.Spawn([]ErasedComponent{Named("B"), ChildOf{Parent: root}})
+
+	member := w.Spawn([]ErasedComponent{
+		Named("Member"),
+		MemberOf{ManyRelationship: ManyRelationship[Members]{Targets: []EntityId{a, b}}},
+	})
+
+	var removed []EntityId
+	w.AddSystems(Update, func(c RemovedComponents[MemberOf]) {
+		removed = slices.AppendSeq(removed, c.Read())
+	})
+
+	// the member is reachable from both, a and b
+	w.Despawn(root)
+	w.RunSchedule(Update)
+
+	require.Equal(t, []EntityId{member}, removed)
+	require.ErrorIs(t, w.TryDespawn(member), ErrNoSuchEntity)
+}
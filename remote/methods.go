@@ -0,0 +1,471 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+
+	"github.com/oliverbestmann/byke"
+)
+
+type method func(world *byke.World, params json.RawMessage) (any, error)
+
+var methods = map[string]method{
+	"world.list_components":   listComponents,
+	"world.list_entities":     listEntities,
+	"world.get_components":    getComponents,
+	"world.insert_components": insertComponents,
+	"world.remove_components": removeComponents,
+	"world.spawn":             spawn,
+	"world.despawn":           despawn,
+	"world.list_resources":    listResources,
+	"world.get_resource":      getResource,
+	"world.list_events":       listEvents,
+	"world.trigger_event":     triggerEvent,
+	"time.pause":              pauseTime,
+	"time.resume":             resumeTime,
+}
+
+// errInvalidParams marks errors caused by the parameters of a request.
+var errInvalidParams = errors.New("invalid params")
+
+// handleRequest applies the request to the world. If the method panicked, the error
+// is returned in the response and ok is false. The world might have been left in an
+// inconsistent state in that case, and no further requests should be applied.
+func handleRequest(world *byke.World, request Request) (response Response, ok bool) {
+	method, found := methods[request.Method]
+	if !found {
+		return errorResponse(request.Id, CodeMethodNotFound, fmt.Errorf("method %q not found", request.Method)), true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Remote method panicked", slog.String("method", request.Method), slog.Any("panic", r))
+
+			response = errorResponse(request.Id, CodeInternalError, fmt.Errorf("%s: %v", request.Method, r))
+			ok = false
+		}
+	}()
+
+	result, err := method(world, request.Params)
+	switch {
+	case errors.Is(err, errInvalidParams):
+		return errorResponse(request.Id, CodeInvalidParams, err), true
+	case err != nil:
+		return errorResponse(request.Id, CodeInternalError, err), true
+	}
+
+	return resultResponse(request.Id, result), true
+}
+
+func decodeParams[P any](params json.RawMessage) (P, error) {
+	var decoded P
+	if len(params) == 0 {
+		return decoded, nil
+	}
+
+	if err := json.Unmarshal(params, &decoded); err != nil {
+		return decoded, fmt.Errorf("%w: %w", errInvalidParams, err)
+	}
+
+	return decoded, nil
+}
+
+func lookupComponentType(world *byke.World, name string) (*byke.ComponentType, error) {
+	componentType, ok := world.TypeRegistry().ComponentType(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown component type %q", errInvalidParams, name)
+	}
+
+	return componentType, nil
+}
+
+func lookupEntity(world *byke.World, entityId byke.EntityId) (byke.EntityRef, error) {
+	entity, ok := world.Entity(entityId)
+	if !ok {
+		return entity, fmt.Errorf("entity %s: %w", entityId, byke.ErrNoSuchEntity)
+	}
+
+	return entity, nil
+}
+
+// decodeComponent decodes a component value of the given type. If base is not nil, the
+// value is decoded on top of a copy of base, allowing partial updates of a component.
+func decodeComponent(componentType *byke.ComponentType, base byke.ErasedComponent, data json.RawMessage) (byke.ErasedComponent, error) {
+	value := componentType.New()
+	if base != nil {
+		value = componentType.CopyOf(base)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("%w: decode %s: %w", errInvalidParams, componentType.Name, err)
+	}
+
+	return value, nil
+}
+
+func listComponents(world *byke.World, _ json.RawMessage) (any, error) {
+	var names []string
+	for _, componentType := range world.TypeRegistry().ComponentTypes() {
+		names = append(names, componentType.Name)
+	}
+
+	return names, nil
+}
+
+type listEntitiesParams struct {
+	// only list entities having all of these components
+	With []string `json:"with"`
+}
+
+type entityInfo struct {
+	Entity     byke.EntityId `json:"entity"`
+	Components []string      `json:"components"`
+}
+
+func listEntities(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[listEntitiesParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	var with []*byke.ComponentType
+	for _, name := range p.With {
+		componentType, err := lookupComponentType(world, name)
+		if err != nil {
+			return nil, err
+		}
+
+		with = append(with, componentType)
+	}
+
+	entities := []entityInfo{}
+
+	for entity := range world.Entities() {
+		if !slices.ContainsFunc(with, func(ty *byke.ComponentType) bool { return !entity.Has(ty) }) {
+			entities = append(entities, entityInfo{
+				Entity:     entity.EntityId(),
+				Components: componentNamesOf(entity),
+			})
+		}
+	}
+
+	slices.SortFunc(entities, func(a, b entityInfo) int {
+		return int(a.Entity) - int(b.Entity)
+	})
+
+	return entities, nil
+}
+
+func componentNamesOf(entity byke.EntityRef) []string {
+	var names []string
+	for _, component := range entity.Components() {
+		names = append(names, component.ComponentType().Name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+type getComponentsParams struct {
+	Entity byke.EntityId `json:"entity"`
+
+	// names of the components to get. Gets all components if empty
+	Components []string `json:"components"`
+}
+
+type getComponentsResult struct {
+	Components map[string]json.RawMessage `json:"components"`
+
+	// errors of components that could not be encoded
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func getComponents(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[getComponentsParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := lookupEntity(world, p.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []byke.ErasedComponent
+
+	if len(p.Components) == 0 {
+		components = entity.Components()
+	}
+
+	for _, name := range p.Components {
+		componentType, err := lookupComponentType(world, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !entity.Has(componentType) {
+			return nil, fmt.Errorf("%w: entity %s has no component %s", errInvalidParams, p.Entity, name)
+		}
+
+		components = append(components, entity.Get(componentType))
+	}
+
+	result := getComponentsResult{
+		Components: map[string]json.RawMessage{},
+	}
+
+	for _, component := range components {
+		name := component.ComponentType().Name
+
+		encoded, err := json.Marshal(component)
+		if err != nil {
+			if result.Errors == nil {
+				result.Errors = map[string]string{}
+			}
+
+			result.Errors[name] = err.Error()
+			continue
+		}
+
+		result.Components[name] = encoded
+	}
+
+	return result, nil
+}
+
+type insertComponentsParams struct {
+	Entity byke.EntityId `json:"entity"`
+
+	// component values by name. Fields missing in a value keep their
+	// current value, if the entity already has the component.
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+func insertComponents(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[insertComponentsParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := lookupEntity(world, p.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []byke.ErasedComponent
+
+	for name, data := range p.Components {
+		componentType, err := lookupComponentType(world, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var base byke.ErasedComponent
+		if entity.Has(componentType) {
+			base = entity.Get(componentType)
+		}
+
+		component, err := decodeComponent(componentType, base, data)
+		if err != nil {
+			return nil, err
+		}
+
+		components = append(components, component)
+	}
+
+	return nil, world.InsertComponents(p.Entity, components...)
+}
+
+type removeComponentsParams struct {
+	Entity     byke.EntityId `json:"entity"`
+	Components []string      `json:"components"`
+}
+
+func removeComponents(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[removeComponentsParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := lookupEntity(world, p.Entity); err != nil {
+		return nil, err
+	}
+
+	// resolve all types first, to not apply the request partially
+	var componentTypes []*byke.ComponentType
+	for _, name := range p.Components {
+		componentType, err := lookupComponentType(world, name)
+		if err != nil {
+			return nil, err
+		}
+
+		componentTypes = append(componentTypes, componentType)
+	}
+
+	for _, componentType := range componentTypes {
+		if err := world.RemoveComponent(p.Entity, componentType); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+type spawnParams struct {
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+func spawn(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[spawnParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []byke.ErasedComponent
+
+	for name, data := range p.Components {
+		componentType, err := lookupComponentType(world, name)
+		if err != nil {
+			return nil, err
+		}
+
+		component, err := decodeComponent(componentType, nil, data)
+		if err != nil {
+			return nil, err
+		}
+
+		components = append(components, component)
+	}
+
+	return world.Spawn(components), nil
+}
+
+type despawnParams struct {
+	Entity byke.EntityId `json:"entity"`
+}
+
+func despawn(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[despawnParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, world.TryDespawn(p.Entity)
+}
+
+func listResources(world *byke.World, _ json.RawMessage) (any, error) {
+	var names []string
+	for _, ty := range world.ResourceTypes() {
+		names = append(names, ty.String())
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+type getResourceParams struct {
+	Resource string `json:"resource"`
+}
+
+func getResource(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[getResourceParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ty := range world.ResourceTypes() {
+		if ty.String() != p.Resource {
+			continue
+		}
+
+		value, _ := world.Resource(ty)
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown resource %q", errInvalidParams, p.Resource)
+}
+
+func listEvents(world *byke.World, _ json.RawMessage) (any, error) {
+	var names []string
+	for _, ty := range world.TypeRegistry().EventTypes() {
+		names = append(names, ty.String())
+	}
+
+	return names, nil
+}
+
+type triggerEventParams struct {
+	Event string          `json:"event"`
+	Value json.RawMessage `json:"value"`
+}
+
+func triggerEvent(world *byke.World, params json.RawMessage) (any, error) {
+	p, err := decodeParams[triggerEventParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType, ok := world.TypeRegistry().EventType(p.Event)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown event type %q, register it using TypeRegistry.RegisterEvent", errInvalidParams, p.Event)
+	}
+
+	eventValue := reflect.New(eventType)
+	if len(p.Value) > 0 {
+		if err := json.Unmarshal(p.Value, eventValue.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: decode %s: %w", errInvalidParams, p.Event, err)
+		}
+	}
+
+	world.TriggerObserver(eventValue.Elem().Interface())
+
+	return nil, nil
+}
+
+// pauseState remembers the scale of the VirtualTime while time is paused.
+type pauseState struct {
+	Paused bool
+	Scale  float32
+}
+
+type timeResult struct {
+	Paused bool    `json:"paused"`
+	Scale  float32 `json:"scale"`
+}
+
+func pauseTime(world *byke.World, _ json.RawMessage) (any, error) {
+	vt, ok := world.ResourceOf[byke.VirtualTime]()
+	if !ok {
+		return nil, errors.New("no VirtualTime resource")
+	}
+
+	state := world.RequireResourceOf[pauseState]()
+	if !state.Paused {
+		state.Paused = true
+		state.Scale = vt.Scale
+		vt.Scale = 0
+	}
+
+	return timeResult{Paused: true, Scale: vt.Scale}, nil
+}
+
+func resumeTime(world *byke.World, _ json.RawMessage) (any, error) {
+	vt, ok := world.ResourceOf[byke.VirtualTime]()
+	if !ok {
+		return nil, errors.New("no VirtualTime resource")
+	}
+
+	state := world.RequireResourceOf[pauseState]()
+	if state.Paused {
+		state.Paused = false
+		vt.Scale = state.Scale
+	}
+
+	return timeResult{Paused: false, Scale: vt.Scale}, nil
+}
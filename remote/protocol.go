@@ -0,0 +1,70 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Error codes as defined by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC 2.0 request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response. Exactly one of Result and Error is set.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("remote error %d: %s", e.Code, e.Message)
+}
+
+func errorResponse(id json.RawMessage, code int, err error) Response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return Response{
+		JSONRPC: "2.0",
+		Id:      id,
+		Error:   &Error{Code: code, Message: err.Error()},
+	}
+}
+
+func resultResponse(id json.RawMessage, result any) Response {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(id, CodeInternalError, fmt.Errorf("encode result: %w", err))
+	}
+
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return Response{
+		JSONRPC: "2.0",
+		Id:      id,
+		Result:  encoded,
+	}
+}
@@ -0,0 +1,225 @@
+// Package remote provides a plugin to inspect and modify a running World from
+// outside the process, using JSON-RPC 2.0 over HTTP.
+//
+// Requests are posted to the root path of the server, e.g.
+//
+//	curl -H 'Content-Type: application/json' -d '{"jsonrpc": "2.0", "id": 1, "method": "world.list_entities"}' http://127.0.0.1:15702/
+//
+// Requests must be sent with a "Content-Type: application/json" header. To protect against
+// requests sent by websites open in a local browser, requests with a non-local Origin header
+// or an unknown Host header are rejected.
+//
+// Requests are not processed by the http server directly. They are queued and
+// applied to the World by a system running in the byke.First schedule, so all
+// world access stays on the main thread.
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/oliverbestmann/byke"
+)
+
+// DefaultAddr is the address the server listens on if Config.Addr is not set.
+const DefaultAddr = "127.0.0.1:15702"
+
+// Config configures the remote Plugin.
+type Config struct {
+	// Addr is the address to listen on. Defaults to DefaultAddr.
+	// Only bind to a non-local address if you trust the network, the
+	// api gives full access to the World.
+	Addr string
+
+	// QueueSize is the maximum number of requests waiting to be applied.
+	// Defaults to 64.
+	QueueSize int
+}
+
+// Plugin serves the remote api on the configured address.
+func Plugin(config Config) byke.Plugin {
+	if config.Addr == "" {
+		config.Addr = DefaultAddr
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = 64
+	}
+
+	return func(app *byke.App) {
+		queue := requestQueue(make(chan *pendingRequest, config.QueueSize))
+
+		app.InsertResource(queue)
+		app.InitResource[pauseState]()
+
+		app.AddSystems(byke.First, handleRequestsSystem)
+
+		listener, err := net.Listen("tcp", config.Addr)
+		if err != nil {
+			slog.Warn("Failed to start remote server", slog.String("addr", config.Addr), slog.String("err", err.Error()))
+			return
+		}
+
+		slog.Info("Remote server listening", slog.String("addr", listener.Addr().String()))
+
+		handler := &server{queue: queue}
+
+		// also accept requests addressed to the configured host
+		if host, _, err := net.SplitHostPort(config.Addr); err == nil && host != "" {
+			handler.hosts = append(handler.hosts, host)
+		}
+
+		go func() {
+			err := http.Serve(listener, handler)
+			if err != nil && !errors.Is(err, net.ErrClosed) {
+				slog.Warn("Remote server stopped", slog.String("err", err.Error()))
+			}
+		}()
+	}
+}
+
+type pendingRequest struct {
+	Request  Request
+	Response chan Response
+}
+
+// requestQueue holds requests received by the http server until
+// they are applied by handleRequestsSystem.
+type requestQueue chan *pendingRequest
+
+// server accepts requests via http and passes them to the requestQueue.
+type server struct {
+	queue requestQueue
+
+	// hosts accepted in the Host header in addition to local hosts
+	hosts []string
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if !isLocalHost(r.Host) && !slices.Contains(s.hosts, hostnameOf(r.Host)) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+
+	// browsers send an Origin header with cross site requests
+	if origin := r.Header.Get("Origin"); origin != "" && !isLocalOrigin(origin) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeResponse(w, errorResponse(nil, CodeParseError, err))
+		return
+	}
+
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		writeResponse(w, errorResponse(request.Id, CodeInvalidRequest, errors.New("invalid request")))
+		return
+	}
+
+	pending := &pendingRequest{
+		Request:  request,
+		Response: make(chan Response, 1),
+	}
+
+	select {
+	case s.queue <- pending:
+	case <-r.Context().Done():
+		return
+	}
+
+	select {
+	case response := <-pending.Response:
+		writeResponse(w, response)
+	case <-r.Context().Done():
+	}
+}
+
+func isLocalOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && isLocalHost(parsed.Host)
+}
+
+// isLocalHost returns true, if host, optionally including a port,
+// refers to the loopback interface.
+func isLocalHost(host string) bool {
+	hostname := hostnameOf(host)
+	if hostname == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hostnameOf strips the port and the brackets of an ipv6 address from host.
+func hostnameOf(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+
+	return strings.Trim(host, "[]")
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Warn("Failed to write remote response", slog.String("err", err.Error()))
+	}
+}
+
+func handleRequestsSystem(world *byke.World) {
+	queue := world.RequireResourceOf[requestQueue]()
+
+	for {
+		select {
+		case pending := <-*queue:
+			response, ok := handleRequest(world, pending.Request)
+			pending.Response <- response
+
+			if !ok {
+				// the world might be in an inconsistent state, do not
+				// apply the requests that are already queued
+				rejectQueuedRequests(*queue, fmt.Errorf("aborted, method %q failed before", pending.Request.Method))
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func rejectQueuedRequests(queue requestQueue, err error) {
+	for {
+		select {
+		case pending := <-queue:
+			pending.Response <- errorResponse(pending.Request.Id, CodeInternalError, err)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/oliverbestmann/byke"
+	"github.com/stretchr/testify/require"
+)
+
+type Health struct {
+	byke.Component[Health]
+	Current int
+	Max     int
+}
+
+type Damage struct {
+	Amount int
+}
+
+func call(t *testing.T, world *byke.World, method string, params any, result any) {
+	t.Helper()
+
+	encoded, err := json.Marshal(params)
+	require.NoError(t, err)
+
+	response, ok := handleRequest(world, Request{
+		JSONRPC: "2.0",
+		Id:      json.RawMessage("1"),
+		Method:  method,
+		Params:  encoded,
+	})
+
+	require.True(t, ok)
+	require.Nil(t, response.Error)
+
+	if result != nil {
+		require.NoError(t, json.Unmarshal(response.Result, result))
+	}
+}
+
+func TestMethods(t *testing.T) {
+	world := byke.NewWorld()
+	world.InsertResource(byke.VirtualTime{Scale: 2})
+	world.InsertResource(pauseState{})
+
+	entityId := world.Spawn([]byke.ErasedComponent{
+		byke.Named("Player"),
+		Health{Current: 5, Max: 10},
+	})
+
+	var entities []entityInfo
+	call(t, world, "world.list_entities", listEntitiesParams{With: []string{"remote.Health"}}, &entities)
+	require.Equal(t, []entityInfo{{Entity: entityId, Components: []string{"byke.Name", "remote.Health"}}}, entities)
+
+	// partial update keeps the value of Max
+	call(t, world, "world.insert_components", map[string]any{
+		"entity":     entityId,
+		"components": map[string]any{"remote.Health": map[string]any{"Current": 7}},
+	}, nil)
+
+	var components getComponentsResult
+	call(t, world, "world.get_components", getComponentsParams{Entity: entityId, Components: []string{"remote.Health"}}, &components)
+	require.JSONEq(t, `{"Current": 7, "Max": 10}`, string(components.Components["remote.Health"]))
+
+	call(t, world, "world.remove_components", removeComponentsParams{Entity: entityId, Components: []string{"remote.Health"}}, nil)
+	entity, _ := world.Entity(entityId)
+	require.False(t, entity.Has(Health{}.ComponentType()))
+
+	// events need to be registered to be triggered remotely
+	world.TypeRegistry().RegisterEvent[Damage]()
+
+	var received []Damage
+	world.AddObserver(byke.NewObserver(func(params byke.On[Damage]) {
+		received = append(received, params.Event)
+	}))
+
+	call(t, world, "world.trigger_event", map[string]any{"event": "remote.Damage", "value": map[string]any{"Amount": 3}}, nil)
+	require.Equal(t, []Damage{{Amount: 3}}, received)
+
+	var result timeResult
+	call(t, world, "time.pause", nil, &result)
+	require.Equal(t, timeResult{Paused: true, Scale: 0}, result)
+
+	call(t, world, "time.resume", nil, &result)
+	require.Equal(t, timeResult{Paused: false, Scale: 2}, result)
+
+	var resources []string
+	call(t, world, "world.list_resources", nil, &resources)
+	require.Contains(t, resources, "byke.VirtualTime")
+
+	response, _ := handleRequest(world, Request{JSONRPC: "2.0", Method: "world.get_components", Params: json.RawMessage(`{"entity": 9999}`)})
+	require.NotNil(t, response.Error)
+	require.Equal(t, CodeInternalError, response.Error.Code)
+
+	response, _ = handleRequest(world, Request{JSONRPC: "2.0", Method: "world.unknown"})
+	require.Equal(t, CodeMethodNotFound, response.Error.Code)
+}
+
+func TestServeHTTP(t *testing.T) {
+	world := byke.NewWorld()
+
+	queue := make(requestQueue, 1)
+	world.InsertResource(queue)
+
+	server := httptest.NewServer(&server{queue: queue})
+	defer server.Close()
+
+	responses := make(chan Response, 1)
+
+	go func() {
+		body := []byte(`{"jsonrpc": "2.0", "id": 42, "method": "world.list_components"}`)
+		resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			close(responses)
+			return
+		}
+
+		defer resp.Body.Close()
+
+		var response Response
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+		responses <- response
+	}()
+
+	// the request is only answered once the system runs
+	for {
+		world.RunSystem(handleRequestsSystem)
+
+		select {
+		case response, ok := <-responses:
+			require.True(t, ok)
+			require.Nil(t, response.Error)
+			require.JSONEq(t, "42", string(response.Id))
+			return
+
+		default:
+		}
+	}
+}
+
+func TestServeHTTPRejectsForeignRequests(t *testing.T) {
+	handler := &server{queue: make(requestQueue, 1)}
+
+	body := `{"jsonrpc": "2.0", "id": 1, "method": "world.list_components"}`
+
+	post := func(modify func(r *http.Request)) int {
+		r := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:15702/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		modify(r)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnsupportedMediaType, post(func(r *http.Request) {
+		r.Header.Set("Content-Type", "text/plain")
+	}))
+
+	require.Equal(t, http.StatusForbidden, post(func(r *http.Request) {
+		r.Header.Set("Origin", "https://example.com")
+	}))
+
+	require.Equal(t, http.StatusForbidden, post(func(r *http.Request) {
+		r.Host = "attacker.example.com:15702"
+	}))
+
+	// local requests are queued
+	require.Equal(t, http.StatusOK, post(func(r *http.Request) {
+		r.Header.Set("Origin", "http://localhost:8080")
+
+		// do not wait for the response
+		ctx, cancel := context.WithCancel(r.Context())
+		cancel()
+
+		*r = *r.WithContext(ctx)
+	}))
+}
+
+func TestPanicAbortsQueuedRequests(t *testing.T) {
+	methods["test.panic"] = func(world *byke.World, params json.RawMessage) (any, error) {
+		panic("broken")
+	}
+
+	defer delete(methods, "test.panic")
+
+	world := byke.NewWorld()
+
+	queue := make(requestQueue, 2)
+	world.InsertResource(queue)
+
+	failing := &pendingRequest{
+		Request:  Request{JSONRPC: "2.0", Method: "test.panic"},
+		Response: make(chan Response, 1),
+	}
+
+	next := &pendingRequest{
+		Request:  Request{JSONRPC: "2.0", Method: "world.spawn"},
+		Response: make(chan Response, 1),
+	}
+
+	queue <- failing
+	queue <- next
+
+	world.RunSystem(handleRequestsSystem)
+
+	require.Equal(t, CodeInternalError, (<-failing.Response).Error.Code)
+	require.Equal(t, CodeInternalError, (<-next.Response).Error.Code)
+
+	// the queued spawn request was not applied
+	require.Empty(t, slices.Collect(world.Entities()))
+}
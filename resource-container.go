@@ -76,6 +76,18 @@ func (rc *resourceContainer) Resource(ty reflect.Type) (AnyPtr, bool) {
 	return resValue.Get()
 }
 
+// ResourceTypes returns the types of all resources currently in the container.
+func (rc *resourceContainer) ResourceTypes() []reflect.Type {
+	var types []reflect.Type
+	for ty, resValue := range *rc {
+		if resValue.IsValid {
+			types = append(types, ty)
+		}
+	}
+
+	return types
+}
+
 func (rc *resourceContainer) ResourceOf[T any]() (*T, bool) {
 	value, ok := rc.Resource(reflect.TypeFor[T]())
 	if !ok {
@@ -143,7 +143,7 @@ func (r *resOptionSystemParamState[T]) GetValue(SystemContext) (reflect.Value, e
 		r.Value = resValue.(*T)
 	}
 
-	return reflect.ValueOf(r).Elem(), nil
+	return reflect.ValueOf((*ResOption[T])(r)).Elem(), nil
 }
 
 func (r *resOptionSystemParamState[T]) CleanupValue() {
@@ -0,0 +1,42 @@
+package byke
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type resOptionCounter struct {
+	Value int
+}
+
+func TestResOption(t *testing.T) {
+	w := NewWorld()
+
+	w.RunSystem(func(counter ResOption[resOptionCounter]) {
+		require.Nil(t, counter.Value)
+	})
+
+	w.InsertResource(resOptionCounter{Value: 1})
+
+	w.RunSystem(func(counter ResOption[resOptionCounter]) {
+		require.NotNil(t, counter.Value)
+		require.Equal(t, 1, counter.Value.Value)
+	})
+
+}
+
+func TestResOptionPredicate(t *testing.T) {
+	var app App
+
+	var runs int
+	app.AddSystems(Update, System(func() { runs++ }).RunIf(ResourceExists[resOptionCounter]))
+
+	app.World().RunSchedule(Update)
+	require.Equal(t, 0, runs)
+
+	app.InsertResource(resOptionCounter{})
+
+	app.World().RunSchedule(Update)
+	require.Equal(t, 1, runs)
+}
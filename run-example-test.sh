@@ -7,4 +7,4 @@ export BYKE_RUN_OFFSCREEN_TEST=true
 export WGPU_FORCE_FALLBACK_ADAPTER=1
 
 cd "$1"
-exec go run --tags fakeClock .
+exec go run .
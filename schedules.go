@@ -2,6 +2,7 @@ package byke
 
 import (
 	"fmt"
+	"runtime"
 	"time"
 )
 
@@ -64,13 +65,23 @@ func configureSchedules(app *App) {
 		StepInterval: 1 * time.Second / 64,
 	})
 
+	app.InsertResource(NewTasks(runtime.GOMAXPROCS(0)))
+
+	app.InsertResource(Prefabs{})
+
 	app.AddSystems(Main, System(updateVirtualTime, runMainSchedule).Chain())
 	app.AddSystems(RunFixedMainLoop, runFixedMainLoopSystem)
 	app.AddSystems(FixedMain, runFixedMainScheduleSystem)
+	app.AddSystems(PreUpdate, System(updatePrefabInstancesSystem).Internal())
 	app.AddSystems(PostUpdate, despawnWithDelaySystem)
+	app.AddSystems(Last, System(compactArchetypesSystem).RunIf(ResourceExists[ArchetypeCompaction]).Internal())
 }
 
 func runMainSchedule(world *World, initialized *Local[bool]) {
+	if profiler := world.Profiler(); profiler != nil {
+		profiler.beginFrame()
+	}
+
 	if !initialized.Value {
 		initialized.Value = true
 
@@ -109,6 +120,7 @@ func runFixedMainLoopSystem(world *World, ft *FixedTime, vt VirtualTime) {
 		ft.Elapsed += step
 		ft.Delta = step
 		ft.DeltaSecs = float32(step.Seconds())
+		ft.steps += 1
 
 		world.RunSchedule(FixedMain)
 	}
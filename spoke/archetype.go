@@ -25,15 +25,22 @@ type Archetype struct {
 	columnsByType map[*ComponentType]Column
 
 	columnAccess []ColumnAccess
+
+	// sparse components of all entities
+	sparse *sparseSets
 }
 
-func makeArchetype(id ArchetypeId, sortedTypes []*ComponentType) *Archetype {
+func makeArchetype(id ArchetypeId, sortedTypes []*ComponentType, sparse *sparseSets) *Archetype {
 	// check that we do not have any duplicates in the types
 	var seen set.Set[*ComponentType]
 	for _, ty := range sortedTypes {
 		if !seen.Insert(ty) {
 			panic(fmt.Sprintf("archetype contains duplicate: %s", ty))
 		}
+
+		if ty.IsSparse {
+			panic(fmt.Sprintf("archetype must not contain sparse type: %s", ty))
+		}
 	}
 
 	columnsByType := map[*ComponentType]Column{}
@@ -54,6 +61,7 @@ func makeArchetype(id ArchetypeId, sortedTypes []*ComponentType) *Archetype {
 		columns:       columns,
 		columnsByType: columnsByType,
 		index:         map[EntityId]Row{},
+		sparse:        sparse,
 	}
 
 	for _, ty := range columns {
@@ -115,6 +123,15 @@ func (a *Archetype) Insert(tick Tick, entityId EntityId, components []ErasedComp
 	a.addEntity(entityId)
 }
 
+// Reserve reserves capacity for the given number of additional entities.
+func (a *Archetype) Reserve(additional int) {
+	a.entities = slices.Grow(a.entities, additional)
+
+	for _, column := range a.columns {
+		column.Reserve(additional)
+	}
+}
+
 func (a *Archetype) addEntity(entityId EntityId) {
 	// put entity into index
 	idx := len(a.entities)
@@ -199,6 +216,14 @@ func (a *Archetype) mustGet(entityId EntityId) EntityRef {
 }
 
 func (a *Archetype) componentAt(row Row, componentType *ComponentType) ErasedComponent {
+	if componentType.IsSparse {
+		if set := a.sparse.lookup(componentType); set != nil {
+			return set.Get(a.entities[row])
+		}
+
+		return nil
+	}
+
 	column := a.getColumn(componentType)
 	if column == nil {
 		return nil
@@ -208,6 +233,14 @@ func (a *Archetype) componentAt(row Row, componentType *ComponentType) ErasedCom
 }
 
 func (a *Archetype) changedAt(row Row, componentType *ComponentType) Tick {
+	if componentType.IsSparse {
+		if set := a.sparse.lookup(componentType); set != nil {
+			return set.Changed(a.entities[row])
+		}
+
+		return NoTick
+	}
+
 	column := a.getColumn(componentType)
 	if column == nil {
 		return NoTick
@@ -216,7 +249,29 @@ func (a *Archetype) changedAt(row Row, componentType *ComponentType) Tick {
 	return column.Changed(row)
 }
 
+func (a *Archetype) markChangedAt(row Row, componentType *ComponentType, tick Tick) {
+	if componentType.IsSparse {
+		if set := a.sparse.lookup(componentType); set != nil {
+			set.MarkChanged(a.entities[row], tick)
+		}
+
+		return
+	}
+
+	if column := a.getColumn(componentType); column != nil {
+		column.MarkChanged(row, tick)
+	}
+}
+
 func (a *Archetype) addedAt(row Row, componentType *ComponentType) Tick {
+	if componentType.IsSparse {
+		if set := a.sparse.lookup(componentType); set != nil {
+			return set.Added(a.entities[row])
+		}
+
+		return NoTick
+	}
+
 	column := a.getColumn(componentType)
 	if column == nil {
 		return NoTick
@@ -243,7 +298,25 @@ func (a *Archetype) LastChanged(componentType *ComponentType) Tick {
 	return column.LastChanged()
 }
 
+func (a *Archetype) hasAt(row Row, componentType *ComponentType) bool {
+	if componentType.IsSparse {
+		return a.sparse.contains(a.entities[row], componentType)
+	}
+
+	return a.ContainsType(componentType)
+}
+
+// getColumn returns the column holding values of the given type. For a sparse
+// type, this returns the column of the types SparseSet.
 func (a *Archetype) getColumn(componentType *ComponentType) Column {
+	if componentType.IsSparse {
+		if set := a.sparse.lookup(componentType); set != nil {
+			return set.column
+		}
+
+		return nil
+	}
+
 	return a.columnsByType[componentType]
 }
 
@@ -271,6 +344,12 @@ func (a *Archetype) IterForQuery(query *Query, fetches []ColumnAccess) (EntityIt
 
 	// check what types we can fetch
 	for _, typ := range query.Fetch {
+		if typ.ComponentType.IsSparse {
+			// values are looked up by entity id during iteration
+			fetch = append(fetch, ColumnAccess{sparse: a.sparse.setOf(typ.ComponentType)})
+			continue
+		}
+
 		column := a.getColumn(typ.ComponentType)
 		fetch = append(fetch, columnAccessOf(column))
 	}
@@ -389,12 +468,7 @@ func (a *Archetype) GetComponent(entityId EntityId, componentType *ComponentType
 		return nil
 	}
 
-	column := a.getColumn(componentType)
-	if column == nil {
-		return nil
-	}
-
-	return column.Get(row)
+	return a.componentAt(row, componentType)
 }
 
 func (a *Archetype) componentsAt(row Row) []ErasedComponent {
@@ -403,6 +477,11 @@ func (a *Archetype) componentsAt(row Row) []ErasedComponent {
 		components[idx] = column.Get(row)
 	}
 
+	// add sparse components too
+	for _, ty := range a.sparse.typesOf(a.entities[row]) {
+		components = append(components, a.componentAt(row, ty))
+	}
+
 	return components
 }
 
@@ -446,7 +525,17 @@ func (e *EntityRef) EntityId() EntityId {
 }
 
 func (e *EntityRef) GetAt(idx int) unsafe.Pointer {
-	return e.fetch.Get(idx).At(e.row)
+	access := e.fetch.Get(idx)
+	if access.sparse != nil {
+		return access.sparse.pointerTo(e.EntityId())
+	}
+
+	return access.At(e.row)
+}
+
+// Has returns true, if the entity has a component of the given type.
+func (e *EntityRef) Has(ty *ComponentType) bool {
+	return e.archetype.hasAt(e.row, ty)
 }
 
 func (e *EntityRef) Get(ty *ComponentType) ErasedComponent {
@@ -457,6 +546,11 @@ func (e *EntityRef) Changed(ty *ComponentType) Tick {
 	return e.archetype.changedAt(e.row, ty)
 }
 
+// MarkChanged marks the component of the given type as changed at the given tick.
+func (e *EntityRef) MarkChanged(ty *ComponentType, tick Tick) {
+	e.archetype.markChangedAt(e.row, ty, tick)
+}
+
 func (e *EntityRef) Added(ty *ComponentType) Tick {
 	return e.archetype.addedAt(e.row, ty)
 }
@@ -468,6 +562,9 @@ func (e *EntityRef) Components() []ErasedComponent {
 type Archetypes struct {
 	archetypes []*Archetype
 	lookup     map[ArchetypeId]*Archetype
+
+	// storage of sparse components, shared by all archetypes
+	sparse *sparseSets
 }
 
 func (a *Archetypes) Lookup(types []*ComponentType) (*Archetype, bool) {
@@ -482,7 +579,7 @@ func (a *Archetypes) Lookup(types []*ComponentType) (*Archetype, bool) {
 		a.lookup = map[ArchetypeId]*Archetype{}
 	}
 
-	at = makeArchetype(id, slices.Clone(sortedTypes))
+	at = makeArchetype(id, slices.Clone(sortedTypes), a.sparse)
 	a.lookup[id] = at
 	a.archetypes = append(a.archetypes, at)
 
@@ -24,6 +24,11 @@ func (c *ShadowComparableColumn[C]) Append(tick Tick, component ErasedComponent)
 	c.shadow.Append(c.unsafeLastValue())
 }
 
+func (c *ShadowComparableColumn[C]) Reserve(additional int) {
+	c.TypedColumn.Reserve(additional)
+	c.shadow.Reserve(additional)
+}
+
 func (c *ShadowComparableColumn[C]) Import(column Column, source Row) {
 	c.TypedColumn.Import(column, source)
 	c.shadow.Append(c.unsafeValue(Row(c.Len() - 1)))
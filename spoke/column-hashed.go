@@ -1,5 +1,7 @@
 package spoke
 
+import "slices"
+
 type HashedComparableColumn[C IsComparableComponent[C]] struct {
 	TypedColumn[C]
 	hashes []HashValue
@@ -14,6 +16,11 @@ func (c *HashedComparableColumn[C]) Append(tick Tick, component ErasedComponent)
 	c.hashes = append(c.hashes, c.hashOf(Row(c.Len()-1)))
 }
 
+func (c *HashedComparableColumn[C]) Reserve(additional int) {
+	c.TypedColumn.Reserve(additional)
+	c.hashes = slices.Grow(c.hashes, additional)
+}
+
 func (c *HashedComparableColumn[C]) Import(column Column, source Row) {
 	c.TypedColumn.Import(column, source)
 	c.hashes = append(c.hashes, c.hashOf(Row(c.Len()-1)))
@@ -7,6 +7,10 @@ import (
 type ColumnAccess struct {
 	base   unsafe.Pointer
 	stride uintptr
+
+	// set for sparse components. Values of a sparse component
+	// can not be accessed by row, see EntityRef.GetAt
+	sparse *SparseSet
 }
 
 func (c *ColumnAccess) At(row Row) unsafe.Pointer {
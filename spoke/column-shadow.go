@@ -1,6 +1,9 @@
 package spoke
 
-import "unsafe"
+import (
+	"slices"
+	"unsafe"
+)
 
 type ShadowColumn struct {
 	ItemSize uintptr
@@ -15,6 +18,10 @@ func (c *ShadowColumn) Append(ptrToValue unsafe.Pointer) {
 	rawCopy(c.ptrTo(idx), ptrToValue, c.ItemSize)
 }
 
+func (c *ShadowColumn) Reserve(additional int) {
+	c.buf = slices.Grow(c.buf, additional*int(c.ItemSize))
+}
+
 func (c *ShadowColumn) Copy(to, from Row) {
 	// this is supposed to not allocate the temporary slice
 	c.buf = append(c.buf, make([]byte, c.ItemSize)...)
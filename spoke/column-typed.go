@@ -2,6 +2,7 @@ package spoke
 
 import (
 	"fmt"
+	"slices"
 	"unsafe"
 )
 
@@ -26,6 +27,18 @@ func (c *TypedColumn[C]) Append(tick Tick, component ErasedComponent) {
 	}
 }
 
+func (c *TypedColumn[C]) Reserve(additional int) {
+	before := unsafe.SliceData(c.values)
+	c.values = slices.Grow(c.values, additional)
+	c.ChangeTracker.reserve(additional)
+
+	if before != unsafe.SliceData(c.values) {
+		for _, onGrow := range c.onGrow {
+			onGrow()
+		}
+	}
+}
+
 func (c *TypedColumn[C]) Update(tick Tick, row Row, component ErasedComponent) {
 	c.values[row] = c.toValue(component)
 	c.ChangeTracker.markChanged(row, tick)
@@ -114,6 +127,11 @@ func (c *ChangeTracker) markChanged(row Row, tick Tick) {
 	c.lastChanged = max(c.lastChanged, tick)
 }
 
+// MarkChanged marks the value in the given row as changed at the given tick.
+func (c *ChangeTracker) MarkChanged(row Row, tick Tick) {
+	c.markChanged(row, tick)
+}
+
 func (c *ChangeTracker) copy(to, from Row) {
 	c.ticks[to] = c.ticks[from]
 }
@@ -128,6 +146,10 @@ func (c *ChangeTracker) append(added, changed Tick) {
 	c.lastChanged = max(c.lastChanged, changed)
 }
 
+func (c *ChangeTracker) reserve(additional int) {
+	c.ticks = slices.Grow(c.ticks, additional)
+}
+
 func (c *ChangeTracker) truncate(n Row) {
 	c.ticks = c.ticks[:n]
 }
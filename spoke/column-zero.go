@@ -1,6 +1,9 @@
 package spoke
 
-import "unsafe"
+import (
+	"slices"
+	"unsafe"
+)
 
 type ZeroSizedColumn[T IsComponent[T]] struct {
 	dummyValue T
@@ -21,6 +24,10 @@ func (c *ZeroSizedColumn[T]) Append(tick Tick, component ErasedComponent) {
 	c.lastAdded = tick
 }
 
+func (c *ZeroSizedColumn[T]) Reserve(additional int) {
+	c.added = slices.Grow(c.added, additional)
+}
+
 func (c *ZeroSizedColumn[T]) Update(tick Tick, row Row, component ErasedComponent) {
 	// zero values do not change
 }
@@ -70,6 +77,10 @@ func (c *ZeroSizedColumn[T]) Len() int {
 	return len(c.added)
 }
 
+func (c *ZeroSizedColumn[T]) MarkChanged(row Row, tick Tick) {
+	// zero values do not change
+}
+
 func (c *ZeroSizedColumn[T]) CheckChanged(tick Tick) {
 	// no zero sized component will ever change
 }
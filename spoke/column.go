@@ -11,6 +11,7 @@ type Row uint32
 
 type Column interface {
 	Append(tick Tick, component ErasedComponent)
+	Reserve(additional int)
 	Import(column Column, source Row)
 	Update(tick Tick, row Row, component ErasedComponent)
 	Copy(from, to Row)
@@ -19,6 +20,7 @@ type Column interface {
 	Access() ColumnAccess
 	Len() int
 	CheckChanged(tick Tick)
+	MarkChanged(row Row, tick Tick)
 	OnGrow(onGrow func())
 	Added(row Row) Tick
 	LastAdded() Tick
@@ -0,0 +1,122 @@
+package spoke
+
+import "slices"
+
+// CompactionStats reports what was reclaimed by Storage.Compact.
+type CompactionStats struct {
+	// Number of empty archetypes removed
+	Archetypes int
+
+	// Number of cached transitions in the ArchetypeGraph removed
+	Transitions int
+
+	// Number of archetype accessors removed from cached queries
+	QueryAccessors int
+}
+
+// Add returns the sum of both stats.
+func (c CompactionStats) Add(other CompactionStats) CompactionStats {
+	return CompactionStats{
+		Archetypes:     c.Archetypes + other.Archetypes,
+		Transitions:    c.Transitions + other.Transitions,
+		QueryAccessors: c.QueryAccessors + other.QueryAccessors,
+	}
+}
+
+// Compact removes all empty archetypes from the storage, including all transitions
+// to or from an empty archetype and all references to an empty archetype in cached queries.
+// An archetype is re-created, once an entity needs it again.
+func (s *Storage) Compact() CompactionStats {
+	var stats CompactionStats
+
+	removed := s.archetypes.removeEmpty()
+	if len(removed) == 0 {
+		return stats
+	}
+
+	stats.Archetypes = len(removed)
+	stats.Transitions = s.archetypes.removeTransitionsOf(removed)
+	stats.QueryAccessors = s.queryCache.Compact()
+
+	return stats
+}
+
+// EmptyArchetypeCount returns the number of archetypes without any entity.
+func (s *Storage) EmptyArchetypeCount() int {
+	var count int
+	for _, archetype := range s.archetypes.All() {
+		if archetype.Len() == 0 {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+// ArchetypeCount returns the number of archetypes, including the empty ones.
+func (s *Storage) ArchetypeCount() int {
+	return len(s.archetypes.All())
+}
+
+func (a *Archetypes) removeEmpty() []*Archetype {
+	var removed []*Archetype
+
+	a.archetypes = slices.DeleteFunc(a.archetypes, func(archetype *Archetype) bool {
+		if archetype.Len() > 0 {
+			return false
+		}
+
+		delete(a.lookup, archetype.Id)
+		removed = append(removed, archetype)
+
+		return true
+	})
+
+	return removed
+}
+
+func (a *ArchetypeGraph) removeTransitionsOf(archetypes []*Archetype) int {
+	var count int
+
+	for tr, target := range a.transitions {
+		if slices.Contains(archetypes, tr.Archetype) || slices.Contains(archetypes, target) {
+			delete(a.transitions, tr)
+			count += 1
+		}
+	}
+
+	return count
+}
+
+// Compact rebuilds all cached queries and releases unused memory.
+// Returns the number of accessors that were removed.
+func (qc *queryCache) Compact() int {
+	var before int
+	for _, weakQuery := range qc.queries {
+		if query := weakQuery.Value(); query != nil {
+			before += len(query.Accessors)
+		}
+	}
+
+	// rebuild all queries
+	qc.Optimize(nil)
+
+	var after int
+	for _, weakQuery := range qc.queries {
+		query := weakQuery.Value()
+		if query == nil {
+			continue
+		}
+
+		after += len(query.Accessors)
+
+		// shrink the memory if we're using much less than we've allocated
+		if cap(query.Accessors) > 2*len(query.Accessors) {
+			query.Accessors = slices.Clone(query.Accessors)
+		}
+	}
+
+	qc.queries = slices.Clip(qc.queries)
+
+	return before - after
+}
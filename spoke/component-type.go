@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"maps"
 	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -49,6 +50,10 @@ type ComponentType struct {
 	// IsImmutableComponent indicates, that a component can not be injected as
 	// a mutable reference. It can only be updated via commands.
 	IsImmutableComponent bool
+
+	// IsSparse indicates, that values of this type are not stored in
+	// an Archetype, but in a SparseSet keyed by EntityId.
+	IsSparse bool
 }
 
 func ComponentTypeOf[C IsComponent[C]]() *ComponentType {
@@ -119,6 +124,17 @@ func ensureComponentType[C IsComponent[C]](ptrToType unsafe.Pointer, makeType fu
 	}
 }
 
+// ComponentTypes returns all component types known so far, ordered by their id.
+func ComponentTypes() []*ComponentType {
+	types := slices.Collect(maps.Values(*componentTypes.Load()))
+
+	slices.SortFunc(types, func(a, b *ComponentType) int {
+		return int(a.Id) - int(b.Id)
+	})
+
+	return types
+}
+
 func abiTypePointerTo(t reflect.Type) unsafe.Pointer {
 	type eface struct {
 		typ, val unsafe.Pointer
@@ -182,6 +198,21 @@ func makeComparableComponentTypeOf[C IsComparableComponent[C]]() *ComponentType
 	})
 }
 
+func sparseComponentTypeOf[C IsComponent[C]]() *ComponentType {
+	reflectType := reflect.TypeFor[C]()
+	ptrToType := abiTypePointerTo(reflectType)
+
+	if cached, ok := (*componentTypes.Load())[ptrToType]; ok {
+		return cached
+	}
+
+	return ensureComponentType[C](ptrToType, func(id ComponentTypeId) *ComponentType {
+		ty := makeNonComparableComponentType[C](id)
+		ty.IsSparse = true
+		return ty
+	})
+}
+
 func makeNonComparableComponentType[C IsComponent[C]](id ComponentTypeId) *ComponentType {
 	ty := baseComponentTypeOf[C](id)
 
@@ -0,0 +1,8 @@
+package spoke
+
+// Disabled marks an entity as disabled. A Query built by a QueryBuilder does not
+// match disabled entities, unless the query mentions the Disabled type itself,
+// e.g. by fetching it, by filtering on it or by calling QueryBuilder.Allow.
+type Disabled struct {
+	Component[Disabled]
+}
@@ -8,6 +8,16 @@ type FetchComponent struct {
 type QueryBuilder struct {
 	Fetch   []FetchComponent
 	Filters []Filter
+
+	// Component types that are excluded by default (like Disabled),
+	// but should be matched by this query.
+	Allowed []*ComponentType
+}
+
+// Allow opts the query in to match entities with the given component type,
+// even if entities with that type are excluded by default, see Disabled.
+func (q *QueryBuilder) Allow(componentType *ComponentType) {
+	q.Allowed = append(q.Allowed, componentType)
 }
 
 func (q *QueryBuilder) Filter(f Filter) {
@@ -36,6 +46,13 @@ func (q *QueryBuilder) FetchComponent(componentType *ComponentType, optional boo
 }
 
 func (q *QueryBuilder) IsArchetypeOnly() bool {
+	for _, fetch := range q.Fetch {
+		if fetch.ComponentType.IsSparse && !fetch.Optional {
+			// need to check each entity for the sparse component
+			return false
+		}
+	}
+
 	for idx := range q.Filters {
 		if !q.Filters[idx].IsArchetypeOnly() {
 			return false
@@ -46,11 +63,44 @@ func (q *QueryBuilder) IsArchetypeOnly() bool {
 }
 
 func (q *QueryBuilder) Build() Query {
+	builder := *q
+
+	disabled := ComponentTypeOf[Disabled]()
+	if !q.mentions(disabled) {
+		// exclude disabled entities by default. Copy the filters
+		// so we do not modify the filters of the builder itself.
+		builder.Filters = append(q.Filters[:len(q.Filters):len(q.Filters)], Filter{Without: disabled})
+	}
+
 	return Query{
-		Fetch:           q.Fetch,
-		Filters:         q.Filters,
-		IsArchetypeOnly: q.IsArchetypeOnly(),
+		Fetch:           builder.Fetch,
+		Filters:         builder.Filters,
+		IsArchetypeOnly: builder.IsArchetypeOnly(),
+	}
+}
+
+// mentions returns true, if the given component type is fetched, allowed
+// or used in any of the filters of this query.
+func (q *QueryBuilder) mentions(componentType *ComponentType) bool {
+	for _, fetch := range q.Fetch {
+		if fetch.ComponentType == componentType {
+			return true
+		}
+	}
+
+	for _, allowed := range q.Allowed {
+		if allowed == componentType {
+			return true
+		}
 	}
+
+	for idx := range q.Filters {
+		if q.Filters[idx].mentions(componentType) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Query struct {
@@ -66,7 +116,12 @@ type Query struct {
 
 func (q *Query) MatchesArchetype(a *Archetype) bool {
 	for _, ty := range q.Fetch {
-		if !a.ContainsType(ty.ComponentType) && !ty.Optional {
+		if ty.Optional || ty.ComponentType.IsSparse {
+			// sparse types are checked per entity in Matches
+			continue
+		}
+
+		if !a.ContainsType(ty.ComponentType) {
 			return false
 		}
 	}
@@ -98,6 +153,12 @@ func (q *Query) MatchesArchetypeWithQueryContext(qc QueryContext, a *Archetype)
 // Matches must only be run for entities provided by an Archetype that matched MatchesArchetype.
 // If the query IsArchetypeOnly, this method does not need to be called.
 func (q *Query) Matches(ctx QueryContext, entity EntityRef) bool {
+	for _, ty := range q.Fetch {
+		if ty.ComponentType.IsSparse && !ty.Optional && !entity.Has(ty.ComponentType) {
+			return false
+		}
+	}
+
 	for idx := range q.Filters {
 		if !q.Filters[idx].Matches(ctx, entity) {
 			return false
@@ -134,11 +195,29 @@ func (f *Filter) IsZero() bool {
 	return f.With == nil && f.Without == nil && f.Added == nil && f.Changed == nil
 }
 
+func (f *Filter) mentions(componentType *ComponentType) bool {
+	if f.With == componentType || f.Without == componentType || f.Added == componentType || f.Changed == componentType {
+		return true
+	}
+
+	for idx := range f.Or {
+		if f.Or[idx].mentions(componentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (f *Filter) IsArchetypeOnly() bool {
 	if f.Added != nil || f.Changed != nil {
 		return false
 	}
 
+	if isSparse(f.With) || isSparse(f.Without) {
+		return false
+	}
+
 	for idx := range f.Or {
 		if !f.Or[idx].IsArchetypeOnly() {
 			return false
@@ -149,19 +228,20 @@ func (f *Filter) IsArchetypeOnly() bool {
 }
 
 func (f *Filter) MatchesArchetype(a *Archetype) bool {
-	if ty := f.With; ty != nil && !a.ContainsType(ty) {
+	// sparse types are not part of an archetype, they are checked in Matches
+	if ty := f.With; ty != nil && !ty.IsSparse && !a.ContainsType(ty) {
 		return false
 	}
 
-	if ty := f.Without; ty != nil && a.ContainsType(ty) {
+	if ty := f.Without; ty != nil && !ty.IsSparse && a.ContainsType(ty) {
 		return false
 	}
 
-	if ty := f.Added; ty != nil && !a.ContainsType(ty) {
+	if ty := f.Added; ty != nil && !ty.IsSparse && !a.ContainsType(ty) {
 		return false
 	}
 
-	if ty := f.Changed; ty != nil && !a.ContainsType(ty) {
+	if ty := f.Changed; ty != nil && !ty.IsSparse && !a.ContainsType(ty) {
 		return false
 	}
 
@@ -211,6 +291,14 @@ func (f *Filter) MatchesArchetypeWithQueryContext(qc QueryContext, a *Archetype)
 // Matches checks the non-archetype specific checks. This must only be called for
 // an entity from an Archetype accepted by MatchesArchetype.
 func (f *Filter) Matches(qc QueryContext, entity EntityRef) bool {
+	if ty := f.With; isSparse(ty) && !entity.Has(ty) {
+		return false
+	}
+
+	if ty := f.Without; isSparse(ty) && entity.Has(ty) {
+		return false
+	}
+
 	if f.Added != nil {
 		tick := entity.Added(f.Added)
 		if tick == NoTick || tick < qc.LastRun {
@@ -228,8 +316,13 @@ func (f *Filter) Matches(qc QueryContext, entity EntityRef) bool {
 	if len(f.Or) == 0 {
 		return true
 	}
+
 	for idx := range f.Or {
-		if f.Or[idx].Matches(qc, entity) {
+		or := &f.Or[idx]
+
+		// the archetype might have matched because of a different branch
+		// of the or, so we need to check the archetype again
+		if or.MatchesArchetype(entity.archetype) && or.Matches(qc, entity) {
 			return true
 		}
 	}
@@ -237,7 +330,14 @@ func (f *Filter) Matches(qc QueryContext, entity EntityRef) bool {
 	return false
 }
 
+func isSparse(ty *ComponentType) bool {
+	return ty != nil && ty.IsSparse
+}
+
 type QueryContext struct {
 	// Last time that the system running this query was executed
 	LastRun Tick
+
+	// The current tick of the system running this query
+	ThisRun Tick
 }
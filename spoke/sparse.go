@@ -0,0 +1,174 @@
+package spoke
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// SparseSet stores the values of one sparse component type keyed by EntityId.
+// Sparse components are not part of an entities Archetype. Adding or removing
+// them does not move the entity to a different Archetype.
+type SparseSet struct {
+	ComponentType *ComponentType
+
+	column   Column
+	entities []EntityId
+	index    map[EntityId]Row
+}
+
+func newSparseSet(componentType *ComponentType) *SparseSet {
+	if !componentType.IsSparse {
+		panic(fmt.Sprintf("component type %s is not sparse", componentType))
+	}
+
+	return &SparseSet{
+		ComponentType: componentType,
+		column:        componentType.MakeColumn(),
+		index:         map[EntityId]Row{},
+	}
+}
+
+func (s *SparseSet) Contains(entityId EntityId) bool {
+	_, ok := s.index[entityId]
+	return ok
+}
+
+// Insert inserts or updates the component value of the given entity.
+// Returns the value stored in the set and true, if the value was newly added.
+func (s *SparseSet) Insert(tick Tick, entityId EntityId, component ErasedComponent) (ErasedComponent, bool) {
+	if row, ok := s.index[entityId]; ok {
+		s.column.Update(tick, row, component)
+		return s.column.Get(row), false
+	}
+
+	row := Row(len(s.entities))
+	s.column.Append(tick, component)
+	s.entities = append(s.entities, entityId)
+	s.index[entityId] = row
+
+	return s.column.Get(row), true
+}
+
+// Remove removes the component value of the given entity from the set.
+func (s *SparseSet) Remove(entityId EntityId) bool {
+	row, ok := s.index[entityId]
+	if !ok {
+		return false
+	}
+
+	delete(s.index, entityId)
+
+	// same as in Archetype.Remove, move the last value into the
+	// spot of the one to remove
+	rowSwap := Row(len(s.entities) - 1)
+	if row != rowSwap {
+		s.entities[row] = s.entities[rowSwap]
+		s.column.Copy(rowSwap, row)
+		s.index[s.entities[row]] = row
+	}
+
+	s.entities = s.entities[:rowSwap]
+	s.column.Truncate(rowSwap)
+
+	return true
+}
+
+func (s *SparseSet) Get(entityId EntityId) ErasedComponent {
+	row, ok := s.index[entityId]
+	if !ok {
+		return nil
+	}
+
+	return s.column.Get(row)
+}
+
+func (s *SparseSet) Added(entityId EntityId) Tick {
+	row, ok := s.index[entityId]
+	if !ok {
+		return NoTick
+	}
+
+	return s.column.Added(row)
+}
+
+func (s *SparseSet) Changed(entityId EntityId) Tick {
+	row, ok := s.index[entityId]
+	if !ok {
+		return NoTick
+	}
+
+	return s.column.Changed(row)
+}
+
+func (s *SparseSet) MarkChanged(entityId EntityId, tick Tick) {
+	if row, ok := s.index[entityId]; ok {
+		s.column.MarkChanged(row, tick)
+	}
+}
+
+func (s *SparseSet) Len() int {
+	return len(s.entities)
+}
+
+func (s *SparseSet) pointerTo(entityId EntityId) unsafe.Pointer {
+	row, ok := s.index[entityId]
+	if !ok {
+		return nil
+	}
+
+	access := s.column.Access()
+	return access.At(row)
+}
+
+type sparseSets struct {
+	byType map[*ComponentType]*SparseSet
+
+	// all sets in order of creation, to have a deterministic iteration order
+	ordered []*SparseSet
+}
+
+// setOf returns the SparseSet for the given type, creating it if needed.
+func (s *sparseSets) setOf(componentType *ComponentType) *SparseSet {
+	if set, ok := s.byType[componentType]; ok {
+		return set
+	}
+
+	if s.byType == nil {
+		s.byType = map[*ComponentType]*SparseSet{}
+	}
+
+	set := newSparseSet(componentType)
+	s.byType[componentType] = set
+	s.ordered = append(s.ordered, set)
+
+	return set
+}
+
+// lookup returns the SparseSet for the given type, or nil if the set does not exist yet.
+func (s *sparseSets) lookup(componentType *ComponentType) *SparseSet {
+	if s == nil {
+		return nil
+	}
+
+	return s.byType[componentType]
+}
+
+func (s *sparseSets) contains(entityId EntityId, componentType *ComponentType) bool {
+	set := s.lookup(componentType)
+	return set != nil && set.Contains(entityId)
+}
+
+func (s *sparseSets) typesOf(entityId EntityId) []*ComponentType {
+	if s == nil {
+		return nil
+	}
+
+	var types []*ComponentType
+	for _, set := range s.ordered {
+		if set.Contains(entityId) {
+			types = append(types, set.ComponentType)
+		}
+	}
+
+	return types
+}
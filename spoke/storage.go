@@ -3,6 +3,7 @@ package spoke
 import (
 	"fmt"
 	"iter"
+	"slices"
 )
 
 type Storage struct {
@@ -10,44 +11,128 @@ type Storage struct {
 	archetypes        ArchetypeGraph
 	queryCache        queryCache
 
+	// storage for components that are not stored in archetypes
+	sparse sparseSets
+
 	// optional hooks for each component type
 	hooks map[ComponentTypeId]ComponentHooks
+
+	// additional hooks, not occupying the hooks registered with RegisterComponentHooks
+	internalHooks map[ComponentTypeId][]ComponentHooks
 }
 
 func NewStorage() *Storage {
 	storage := &Storage{
 		entityToArchetype: map[EntityId]*Archetype{},
 		hooks:             map[ComponentTypeId]ComponentHooks{},
+		internalHooks:     map[ComponentTypeId][]ComponentHooks{},
 	}
 
 	storage.queryCache.archetypes = &storage.archetypes
+	storage.archetypes.sparse = &storage.sparse
 
 	return storage
 }
 
 func (s *Storage) Spawn(tick Tick, entityId EntityId, components []ErasedComponent) {
-	if _, exists := s.entityToArchetype[entityId]; exists {
-		panic(fmt.Sprintf("entity %s already exists", entityId))
+	// find or create the archetype we fit into
+	archetype := s.archetypeFor(components, nil)
+
+	s.spawnInto(tick, archetype, entityId, components)
+}
+
+// SpawnBatch spawns multiple entities at once. The components of the entity
+// at entityIds[i] are given in components[i]. Capacity for all entities is
+// reserved in their target archetypes before the first entity is inserted.
+func (s *Storage) SpawnBatch(tick Tick, entityIds []EntityId, components [][]ErasedComponent) {
+	if len(entityIds) != len(components) {
+		panic("number of entities and component slices must match")
 	}
 
-	// collect the component types
-	var componentTypes []*ComponentType
+	archetypes := make([]*Archetype, len(entityIds))
+	reserve := map[*Archetype]int{}
+
+	var prev *Archetype
+	for idx := range entityIds {
+		// entities in a batch often have the same components, in that case
+		// we can skip looking up the archetype again
+		prev = s.archetypeFor(components[idx], prev)
+
+		archetypes[idx] = prev
+		reserve[prev] += 1
+	}
+
+	for archetype, count := range reserve {
+		archetype.Reserve(count)
+	}
+
+	for idx, entityId := range entityIds {
+		s.spawnInto(tick, archetypes[idx], entityId, components[idx])
+	}
+}
+
+// archetypeFor returns the archetype for an entity with the given components.
+// If the components match the types of the candidate archetype, the candidate is returned.
+func (s *Storage) archetypeFor(components []ErasedComponent, candidate *Archetype) *Archetype {
+	var denseTypes []*ComponentType
 	for _, component := range components {
-		componentTypes = append(componentTypes, component.ComponentType())
+		if componentType := component.ComponentType(); !componentType.IsSparse {
+			denseTypes = append(denseTypes, componentType)
+		}
 	}
 
-	// find or create the archetype we fit into
-	archetype, created := s.archetypes.Lookup(componentTypes)
+	if candidate != nil && len(candidate.Types) == len(denseTypes) {
+		matches := true
+		for _, ty := range denseTypes {
+			if !candidate.ContainsType(ty) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return candidate
+		}
+	}
+
+	archetype, created := s.archetypes.Lookup(denseTypes)
 	if created {
 		s.handleNewArchetype(archetype)
 	}
 
+	return archetype
+}
+
+func (s *Storage) spawnInto(tick Tick, archetype *Archetype, entityId EntityId, components []ErasedComponent) {
+	if _, exists := s.entityToArchetype[entityId]; exists {
+		panic(fmt.Sprintf("entity %s already exists", entityId))
+	}
+
+	// collect the component types
+	componentTypes := make([]*ComponentType, 0, len(components))
+	denseComponents := make([]ErasedComponent, 0, len(components))
+	for _, component := range components {
+		componentType := component.ComponentType()
+		componentTypes = append(componentTypes, componentType)
+
+		if !componentType.IsSparse {
+			denseComponents = append(denseComponents, component)
+		}
+	}
+
 	// add entity to the archetype
-	archetype.Insert(tick, entityId, components)
+	archetype.Insert(tick, entityId, denseComponents)
 
 	// remember where we put the entity
 	s.entityToArchetype[entityId] = archetype
 
+	// sparse components are stored outside the archetype
+	for _, component := range components {
+		if component.ComponentType().IsSparse {
+			s.sparse.setOf(component.ComponentType()).Insert(tick, entityId, component)
+		}
+	}
+
 	// call hooks
 	s.dispatchOnAdd(archetype, entityId, componentTypes)
 	s.dispatchOnInsert(archetype, entityId, componentTypes)
@@ -59,20 +144,28 @@ func (s *Storage) Despawn(entityId EntityId) bool {
 		return false
 	}
 
+	componentTypes := archetype.Types
+
+	sparseTypes := s.sparse.typesOf(entityId)
+	if len(sparseTypes) > 0 {
+		componentTypes = append(slices.Clone(componentTypes), sparseTypes...)
+	}
+
 	// call hooks before removing the entity
-	s.dispatchOnDiscard(archetype, entityId, archetype.Types)
-	s.dispatchOnRemove(archetype, entityId, archetype.Types)
-	s.dispatchOnDespawn(archetype, entityId, archetype.Types)
+	s.dispatchOnDiscard(archetype, entityId, componentTypes)
+	s.dispatchOnRemove(archetype, entityId, componentTypes)
+	s.dispatchOnDespawn(archetype, entityId, componentTypes)
 
 	archetype.Remove(entityId)
 
+	for _, ty := range sparseTypes {
+		s.sparse.lookup(ty).Remove(entityId)
+	}
+
 	delete(s.entityToArchetype, entityId)
 
-	if archetype.Len() == 0 {
-		// TODO maybe remove the archetype from the graph if it is empty?
-		//  that might speed up queries matching a lot of empty archetypes
-		//  we would also need to call the optimizer again
-	}
+	// an archetype might be empty now. Empty archetypes are
+	// reclaimed by calling Compact.
 
 	return true
 }
@@ -88,9 +181,23 @@ func (s *Storage) InsertComponents(tick Tick, entityId EntityId, components []Er
 	var addedComponentTypes []*ComponentType
 	var updatedComponentTypes []*ComponentType
 
+	// components that need to be added to the new archetype
+	var addedComponents []ErasedComponent
+
 	var created, anyCreated bool
 	for _, component := range components {
 		componentType := component.ComponentType()
+
+		if componentType.IsSparse {
+			if s.sparse.contains(entityId, componentType) {
+				updatedComponentTypes = append(updatedComponentTypes, componentType)
+			} else {
+				addedComponentTypes = append(addedComponentTypes, componentType)
+			}
+
+			continue
+		}
+
 		if newArchetype.ContainsType(componentType) {
 			updatedComponentTypes = append(updatedComponentTypes, componentType)
 			continue
@@ -102,6 +209,7 @@ func (s *Storage) InsertComponents(tick Tick, entityId EntityId, components []Er
 
 		// remember added types to call the correct hooks later
 		addedComponentTypes = append(addedComponentTypes, componentType)
+		addedComponents = append(addedComponents, component)
 	}
 
 	if anyCreated {
@@ -112,20 +220,37 @@ func (s *Storage) InsertComponents(tick Tick, entityId EntityId, components []Er
 	// but only for the types that are being updated
 	s.dispatchOnDiscard(prevArchetype, entityId, updatedComponentTypes)
 
-	if newArchetype == prevArchetype {
-		// no change in archetypes, update in existing archetype
-		for idx, component := range components {
-			components[idx] = prevArchetype.ReplaceComponentValue(tick, entityId, component)
+	// update the values of all components that the entity already has
+	// and put sparse components into their sets
+	for _, component := range components {
+		componentType := component.ComponentType()
+
+		switch {
+		case componentType.IsSparse:
+			s.sparse.setOf(componentType).Insert(tick, entityId, component)
+
+		case prevArchetype.ContainsType(componentType):
+			prevArchetype.ReplaceComponentValue(tick, entityId, component)
 		}
+	}
 
-		// call insert hook after updating the entity
+	if newArchetype == prevArchetype {
+		// no change in archetypes, call insert hook after updating the entity
 		s.dispatchOnInsert(newArchetype, entityId, updatedComponentTypes)
 
+		// call hooks for newly added sparse components
+		s.dispatchOnAdd(newArchetype, entityId, addedComponentTypes)
+		s.dispatchOnInsert(newArchetype, entityId, addedComponentTypes)
+
+		for idx, component := range components {
+			components[idx] = newArchetype.GetComponent(entityId, component.ComponentType())
+		}
+
 		return
 	}
 
 	// transfer our entity
-	newArchetype.Import(tick, prevArchetype, entityId, components...)
+	newArchetype.Import(tick, prevArchetype, entityId, addedComponents...)
 
 	// remove from the previous archetype
 	prevArchetype.Remove(entityId)
@@ -150,6 +275,34 @@ func (s *Storage) InsertComponents(tick Tick, entityId EntityId, components []Er
 	}
 }
 
+// ReserveInsert reserves capacity to insert a component of the given type
+// into all the given entities.
+func (s *Storage) ReserveInsert(entityIds []EntityId, componentType *ComponentType) {
+	if componentType.IsSparse {
+		return
+	}
+
+	reserve := map[*Archetype]int{}
+
+	for _, entityId := range entityIds {
+		archetype, ok := s.entityToArchetype[entityId]
+		if !ok || archetype.ContainsType(componentType) {
+			continue
+		}
+
+		target, created := s.archetypes.NextWith(archetype, componentType)
+		if created {
+			s.handleNewArchetype(target)
+		}
+
+		reserve[target] += 1
+	}
+
+	for archetype, count := range reserve {
+		archetype.Reserve(count)
+	}
+}
+
 func (s *Storage) InsertComponent(tick Tick, entityId EntityId, component ErasedComponent) ErasedComponent {
 	archetype, ok := s.entityToArchetype[entityId]
 	if !ok {
@@ -157,6 +310,10 @@ func (s *Storage) InsertComponent(tick Tick, entityId EntityId, component Erased
 	}
 
 	componentType := component.ComponentType()
+	if componentType.IsSparse {
+		return s.insertSparseComponent(tick, archetype, entityId, component)
+	}
+
 	if archetype.ContainsType(componentType) {
 		// call hook to discard the previous value
 		s.dispatchOnDiscard(archetype, entityId, []*ComponentType{componentType})
@@ -197,12 +354,39 @@ func (s *Storage) InsertComponent(tick Tick, entityId EntityId, component Erased
 	return componentValue
 }
 
+func (s *Storage) insertSparseComponent(tick Tick, archetype *Archetype, entityId EntityId, component ErasedComponent) ErasedComponent {
+	componentType := component.ComponentType()
+	componentTypes := []*ComponentType{componentType}
+
+	set := s.sparse.setOf(componentType)
+
+	exists := set.Contains(entityId)
+	if exists {
+		// call hook to discard the previous value
+		s.dispatchOnDiscard(archetype, entityId, componentTypes)
+	}
+
+	componentValue, _ := set.Insert(tick, entityId, component)
+
+	if !exists {
+		s.dispatchOnAdd(archetype, entityId, componentTypes)
+	}
+
+	s.dispatchOnInsert(archetype, entityId, componentTypes)
+
+	return componentValue
+}
+
 func (s *Storage) RemoveComponent(tick Tick, entityId EntityId, componentType *ComponentType) (ErasedComponent, bool) {
 	archetype, ok := s.entityToArchetype[entityId]
 	if !ok {
 		panic(fmt.Sprintf("entity %s does not exist", entityId))
 	}
 
+	if componentType.IsSparse {
+		return s.removeSparseComponent(archetype, entityId, componentType)
+	}
+
 	if !archetype.ContainsType(componentType) {
 		// entity does not have the component in question
 		return nil, false
@@ -237,6 +421,23 @@ func (s *Storage) RemoveComponent(tick Tick, entityId EntityId, componentType *C
 	return copyOfComponent, true
 }
 
+func (s *Storage) removeSparseComponent(archetype *Archetype, entityId EntityId, componentType *ComponentType) (ErasedComponent, bool) {
+	set := s.sparse.lookup(componentType)
+	if set == nil || !set.Contains(entityId) {
+		return nil, false
+	}
+
+	// call hooks before removing the component
+	s.dispatchOnDiscard(archetype, entityId, []*ComponentType{componentType})
+	s.dispatchOnRemove(archetype, entityId, []*ComponentType{componentType})
+
+	copyOfComponent := componentType.CopyOf(set.Get(entityId))
+
+	set.Remove(entityId)
+
+	return copyOfComponent, true
+}
+
 func (s *Storage) Get(entityId EntityId) (EntityRef, bool) {
 	archetype, ok := s.entityToArchetype[entityId]
 	if !ok {
@@ -281,6 +482,14 @@ func (s *Storage) CheckChanged(tick Tick, query *CachedQuery, types []*Component
 			continue
 		}
 
+		if ty.IsSparse {
+			if set := s.sparse.lookup(ty); set != nil {
+				set.column.CheckChanged(tick)
+			}
+
+			continue
+		}
+
 		for idx := range query.Accessors {
 			ac := &query.Accessors[idx]
 
@@ -362,6 +571,10 @@ func (s *Storage) HasComponent(entityId EntityId, componentType *ComponentType)
 		return false
 	}
 
+	if componentType.IsSparse {
+		return s.sparse.contains(entityId, componentType)
+	}
+
 	return archetype.ContainsType(componentType)
 }
 
@@ -377,43 +590,44 @@ func (s *Storage) RegisterComponentHooks(componentType *ComponentType) *Register
 	}
 }
 
+// AddInternalComponentHooks adds hooks for a component type that are called after the hooks
+// registered using RegisterComponentHooks. Any number of internal hooks can be added for the
+// same component type. They are meant for bookkeeping, e.g. to maintain an index of component values.
+func (s *Storage) AddInternalComponentHooks(componentType *ComponentType, hooks ComponentHooks) {
+	s.internalHooks[componentType.Id] = append(s.internalHooks[componentType.Id], hooks)
+}
+
 func (s *Storage) dispatchOnAdd(archetype *Archetype, entityId EntityId, components []*ComponentType) {
-	for _, ty := range components {
-		if hook := s.hooks[ty.Id].OnAdd; hook != nil {
-			hook(archetype.mustGet(entityId), ty)
-		}
-	}
+	s.dispatchHooks(archetype, entityId, components, func(hooks ComponentHooks) ComponentHook { return hooks.OnAdd })
 }
 
 func (s *Storage) dispatchOnInsert(archetype *Archetype, entityId EntityId, components []*ComponentType) {
-	for _, ty := range components {
-		if hook := s.hooks[ty.Id].OnInsert; hook != nil {
-			hook(archetype.mustGet(entityId), ty)
-		}
-	}
+	s.dispatchHooks(archetype, entityId, components, func(hooks ComponentHooks) ComponentHook { return hooks.OnInsert })
 }
 
 func (s *Storage) dispatchOnDiscard(archetype *Archetype, entityId EntityId, components []*ComponentType) {
-	for _, ty := range components {
-		if hook := s.hooks[ty.Id].OnDiscard; hook != nil {
-			hook(archetype.mustGet(entityId), ty)
-		}
-	}
+	s.dispatchHooks(archetype, entityId, components, func(hooks ComponentHooks) ComponentHook { return hooks.OnDiscard })
 }
 
 func (s *Storage) dispatchOnRemove(archetype *Archetype, entityId EntityId, components []*ComponentType) {
-	for _, ty := range components {
-		if hook := s.hooks[ty.Id].OnRemove; hook != nil {
-			hook(archetype.mustGet(entityId), ty)
-		}
-	}
+	s.dispatchHooks(archetype, entityId, components, func(hooks ComponentHooks) ComponentHook { return hooks.OnRemove })
 }
 
 func (s *Storage) dispatchOnDespawn(archetype *Archetype, entityId EntityId, components []*ComponentType) {
+	s.dispatchHooks(archetype, entityId, components, func(hooks ComponentHooks) ComponentHook { return hooks.OnDespawn })
+}
+
+func (s *Storage) dispatchHooks(archetype *Archetype, entityId EntityId, components []*ComponentType, hookOf func(hooks ComponentHooks) ComponentHook) {
 	for _, ty := range components {
-		if hook := s.hooks[ty.Id].OnDespawn; hook != nil {
+		if hook := hookOf(s.hooks[ty.Id]); hook != nil {
 			hook(archetype.mustGet(entityId), ty)
 		}
+
+		for _, hooks := range s.internalHooks[ty.Id] {
+			if hook := hookOf(hooks); hook != nil {
+				hook(archetype.mustGet(entityId), ty)
+			}
+		}
 	}
 }
 
@@ -193,3 +193,164 @@ func TestStorage_OptimizeQuery(t *testing.T) {
 	_, ok = iter.Next()
 	require.False(t, ok)
 }
+
+type Selected struct {
+	SparseComponent[Selected]
+}
+
+type Health struct {
+	SparseComponent[Health]
+	Value int
+}
+
+func TestStorage_Sparse(t *testing.T) {
+	var tick Tick = 1
+
+	s := NewStorage()
+
+	s.Spawn(tick, 1, []ErasedComponent{&Position{X: 1}, &Health{Value: 10}})
+	s.Spawn(tick, 2, []ErasedComponent{&Position{X: 2}})
+	s.Spawn(tick, 3, []ErasedComponent{&Position{X: 3}, &Selected{}})
+
+	// sparse components do not end up in an archetype
+	require.Same(t, s.entityToArchetype[1], s.entityToArchetype[2])
+	require.Same(t, s.entityToArchetype[1], s.entityToArchetype[3])
+
+	queryIdsWithContext := func(query Query, qc QueryContext) []EntityId {
+		var entityIds []EntityId
+
+		iter := s.IterQuery(s.OptimizeQuery(query), qc)
+		for entity := range iter.AsSeq() {
+			entityIds = append(entityIds, entity.EntityId())
+		}
+
+		return entityIds
+	}
+
+	queryIds := func(query Query) []EntityId {
+		return queryIdsWithContext(query, QueryContext{})
+	}
+
+	healthType := ComponentTypeOf[Health]()
+	selectedType := ComponentTypeOf[Selected]()
+
+	fetchHealth := QueryBuilder{Fetch: []FetchComponent{{ComponentType: healthType}}}
+	require.Equal(t, []EntityId{1}, queryIds(fetchHealth.Build()))
+
+	withSelected := QueryBuilder{Filters: []Filter{{With: selectedType}}}
+	require.Equal(t, []EntityId{3}, queryIds(withSelected.Build()))
+
+	withoutSelected := QueryBuilder{Filters: []Filter{{Without: selectedType}}}
+	require.Equal(t, []EntityId{1, 2}, queryIds(withoutSelected.Build()))
+
+	// toggling the marker does not move the entity
+	archetype := s.entityToArchetype[2]
+	s.InsertComponent(tick, 2, &Selected{})
+	require.Same(t, archetype, s.entityToArchetype[2])
+	require.Equal(t, []EntityId{2, 3}, queryIds(withSelected.Build()))
+
+	_, removed := s.RemoveComponent(tick, 3, selectedType)
+	require.True(t, removed)
+	require.Equal(t, []EntityId{2}, queryIds(withSelected.Build()))
+
+	// fetch the value using the column access
+	query := s.OptimizeQuery(fetchHealth.Build())
+	iter := s.IterQuery(query, QueryContext{})
+	entity, ok := iter.Next()
+	require.True(t, ok)
+	require.Equal(t, 10, (*Health)(entity.GetAt(0)).Value)
+
+	// optional fetch returns nil for entities without the component
+	optionalHealth := QueryBuilder{Fetch: []FetchComponent{{ComponentType: healthType, Optional: true}}}
+	iter = s.IterQuery(s.OptimizeQuery(optionalHealth.Build()), QueryContext{})
+	for entity := range iter.AsSeq() {
+		require.Equal(t, entity.EntityId() == 1, entity.GetAt(0) != nil)
+	}
+
+	// added filter works per entity
+	tick += 1
+	s.InsertComponents(tick, 2, []ErasedComponent{&Health{Value: 20}, &Velocity{X: 1}})
+	addedHealth := QueryBuilder{Filters: []Filter{{Added: healthType}}}
+	require.Equal(t, []EntityId{2}, queryIdsWithContext(addedHealth.Build(), QueryContext{LastRun: tick}))
+
+	require.True(t, s.HasComponent(2, healthType))
+	require.True(t, s.Despawn(2))
+	require.False(t, s.sparse.contains(2, healthType))
+	require.False(t, s.sparse.contains(2, selectedType))
+}
+
+func TestStorage_InsertComponentsUpdatesExisting(t *testing.T) {
+	s := NewStorage()
+
+	s.Spawn(1, 1, []ErasedComponent{&Position{X: 1}})
+	s.InsertComponents(2, 1, []ErasedComponent{&Position{X: 2}, &Velocity{X: 3}})
+
+	entity, _ := s.Get(1)
+	require.Equal(t, &Position{X: 2}, entity.Get(ComponentTypeOf[Position]()))
+	require.Equal(t, &Velocity{X: 3}, entity.Get(ComponentTypeOf[Velocity]()))
+	require.Equal(t, 1, s.entityToArchetype[1].Len())
+}
+
+func TestStorage_InternalComponentHooks(t *testing.T) {
+	s := NewStorage()
+
+	var calls []string
+	record := func(name string) ComponentHook {
+		return func(entity EntityRef, component *ComponentType) {
+			calls = append(calls, name)
+		}
+	}
+
+	s.RegisterComponentHooks(ComponentTypeOf[Position]()).OnInsert(record("hook"))
+
+	// internal hooks do not occupy the hook slots and can be added multiple times
+	s.AddInternalComponentHooks(ComponentTypeOf[Position](), ComponentHooks{OnInsert: record("a")})
+	s.AddInternalComponentHooks(ComponentTypeOf[Position](), ComponentHooks{OnInsert: record("b"), OnDiscard: record("discard")})
+
+	s.Spawn(1, 1, []ErasedComponent{&Position{X: 1}})
+	require.Equal(t, []string{"hook", "a", "b"}, calls)
+
+	calls = nil
+	s.Despawn(1)
+	require.Equal(t, []string{"discard"}, calls)
+}
+
+func TestStorage_Compact(t *testing.T) {
+	s := NewStorage()
+
+	s.Spawn(1, 1, []ErasedComponent{&Position{X: 1}})
+	s.Spawn(1, 2, []ErasedComponent{&Position{X: 2}})
+
+	query := s.OptimizeQuery(Query{
+		Fetch: []FetchComponent{{ComponentType: ComponentTypeOf[Position]()}},
+	})
+
+	// move entity 2 through a few archetypes, leaving empty ones behind
+	s.InsertComponent(1, 2, &Velocity{X: 1})
+	s.RemoveComponent(1, 2, ComponentTypeOf[Position]())
+
+	require.Equal(t, 3, s.ArchetypeCount())
+	require.Equal(t, 1, s.EmptyArchetypeCount())
+	require.Len(t, query.Accessors, 2)
+
+	stats := s.Compact()
+	require.Equal(t, CompactionStats{Archetypes: 1, Transitions: 2, QueryAccessors: 1}, stats)
+
+	require.Equal(t, 2, s.ArchetypeCount())
+	require.Equal(t, 0, s.EmptyArchetypeCount())
+	require.Len(t, query.Accessors, 1)
+
+	// nothing left to reclaim
+	require.Equal(t, CompactionStats{}, s.Compact())
+
+	// the removed archetype is created again if needed
+	s.InsertComponent(1, 2, &Position{X: 3})
+
+	var count int
+	iter := s.IterQuery(query, QueryContext{})
+	for range iter.AsSeq() {
+		count += 1
+	}
+
+	require.Equal(t, 2, count)
+}
@@ -56,6 +56,21 @@ func (ComparableComponent[T]) isComparableComponent(componentMarkerType) {}
 
 func (ComparableComponent[T]) supportsChangeDetection(componentMarkerType) {}
 
+// SparseComponent marks a component to be stored in a SparseSet instead of an
+// Archetype column. Adding or removing a sparse component does not move the
+// entity to a different Archetype, which makes it a good fit for markers that
+// are toggled frequently. Iterating sparse components is slower than iterating
+// components stored in archetypes.
+type SparseComponent[C IsComponent[C]] struct{}
+
+func (SparseComponent[C]) IsComponent(C) {}
+
+func (SparseComponent[C]) isComponent(isComponentMarker) {}
+
+func (SparseComponent[C]) ComponentType() *ComponentType {
+	return sparseComponentTypeOf[C]()
+}
+
 type componentMarkerType struct{}
 
 type IsErasedComparableComponent interface {
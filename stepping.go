@@ -0,0 +1,240 @@
+package byke
+
+import (
+	"slices"
+)
+
+type steppingAction uint8
+
+const (
+	steppingWait steppingAction = iota
+	steppingStep
+	steppingContinue
+)
+
+// steppingCursor points to a system within the schedules added to Stepping.
+type steppingCursor struct {
+	Schedule int
+	System   int
+}
+
+func (c steppingCursor) less(other steppingCursor) bool {
+	return c.Schedule < other.Schedule || c.Schedule == other.Schedule && c.System < other.System
+}
+
+type steppingSystem struct {
+	ScheduleId ScheduleId
+	SystemId   SystemId
+	Name       string
+}
+
+func (s steppingSystem) matches(scheduleId ScheduleId, system *preparedSystem) bool {
+	if s.ScheduleId != scheduleId {
+		return false
+	}
+
+	if s.Name != "" {
+		return s.Name == system.Name
+	}
+
+	return s.SystemId == system.Id
+}
+
+// Stepping is a resource that allows to step through the systems of a schedule
+// one by one. Insert it into the world and add the schedules to step through using
+// AddSchedule. Once enabled, the systems of those schedules do not run until
+// StepSystem or ContinueFrame is called. All other schedules keep running as usual.
+//
+// Systems are identified either by the system function itself, or by their name as a string,
+// e.g. "byke.updateVirtualTime".
+//
+// Systems marked using Systems.Internal, like the systems updating message buffers,
+// are never paused.
+type Stepping struct {
+	enabled   bool
+	schedules []ScheduleId
+
+	action       steppingAction
+	cursor       steppingCursor
+	cursorSystem string
+
+	// true if the cursor was stopped at a breakpoint
+	atBreakpoint bool
+
+	alwaysRun   []steppingSystem
+	breakpoints []steppingSystem
+}
+
+// AddSchedule adds a schedule to step through. Schedules must be added in the
+// order they are executed within a frame.
+func (s *Stepping) AddSchedule(scheduleId ScheduleId) *Stepping {
+	if !slices.Contains(s.schedules, scheduleId) {
+		s.schedules = append(s.schedules, scheduleId)
+	}
+
+	return s
+}
+
+// Enable enables stepping. The schedules are paused at the current
+// position until StepSystem or ContinueFrame is called.
+func (s *Stepping) Enable() {
+	if s.enabled {
+		return
+	}
+
+	s.enabled = true
+	s.action = steppingWait
+	s.cursor = steppingCursor{}
+	s.cursorSystem = ""
+	s.atBreakpoint = false
+}
+
+// Disable disables stepping. All systems run normally again.
+func (s *Stepping) Disable() {
+	s.enabled = false
+}
+
+// IsEnabled returns true, if stepping is enabled.
+func (s *Stepping) IsEnabled() bool {
+	return s.enabled
+}
+
+// StepSystem runs the next system during the next execution of its schedule.
+func (s *Stepping) StepSystem() {
+	s.action = steppingStep
+}
+
+// ContinueFrame runs all remaining systems up to the end of the frame,
+// or until a system with a breakpoint is reached.
+func (s *Stepping) ContinueFrame() {
+	s.action = steppingContinue
+}
+
+// AlwaysRun marks a system of a schedule to always run, even if stepping is
+// enabled. Use this for systems like input handling, that keep the application responsive.
+func (s *Stepping) AlwaysRun(scheduleId ScheduleId, system AnySystem) *Stepping {
+	s.alwaysRun = append(s.alwaysRun, steppingSystemOf(scheduleId, system))
+	return s
+}
+
+// SetBreakpoint sets a breakpoint on a system. ContinueFrame stops before executing it.
+func (s *Stepping) SetBreakpoint(scheduleId ScheduleId, system AnySystem) *Stepping {
+	s.breakpoints = append(s.breakpoints, steppingSystemOf(scheduleId, system))
+	return s
+}
+
+// ClearBreakpoint removes a breakpoint previously set using SetBreakpoint.
+func (s *Stepping) ClearBreakpoint(scheduleId ScheduleId, system AnySystem) *Stepping {
+	breakpoint := steppingSystemOf(scheduleId, system)
+	s.breakpoints = slices.DeleteFunc(s.breakpoints, func(other steppingSystem) bool { return other == breakpoint })
+	return s
+}
+
+// Cursor returns the schedule and the name of the next system to run.
+// The name is only known after the schedule was executed once while stepping was enabled.
+func (s *Stepping) Cursor() (ScheduleId, string, bool) {
+	if !s.enabled || s.cursorSystem == "" || s.cursor.Schedule >= len(s.schedules) {
+		return nil, "", false
+	}
+
+	return s.schedules[s.cursor.Schedule], s.cursorSystem, true
+}
+
+func steppingSystemOf(scheduleId ScheduleId, system AnySystem) steppingSystem {
+	if name, ok := system.(string); ok {
+		return steppingSystem{ScheduleId: scheduleId, Name: name}
+	}
+
+	return steppingSystem{ScheduleId: scheduleId, SystemId: asSystemConfig(system).Id}
+}
+
+func (s *Stepping) matchesAny(systems []steppingSystem, scheduleId ScheduleId, system *preparedSystem) bool {
+	return slices.ContainsFunc(systems, func(other steppingSystem) bool {
+		return other.matches(scheduleId, system)
+	})
+}
+
+// shouldRun is called by World.RunSchedule for each system of a schedule
+// and returns true, if the system is allowed to run.
+func (s *Stepping) shouldRun(scheduleId ScheduleId, systemIdx int, system *preparedSystem) bool {
+	if !s.enabled {
+		return true
+	}
+
+	scheduleIdx := slices.Index(s.schedules, scheduleId)
+	if scheduleIdx < 0 {
+		return true
+	}
+
+	cursor := steppingCursor{Schedule: scheduleIdx, System: systemIdx}
+
+	if system.Internal || s.matchesAny(s.alwaysRun, scheduleId, system) {
+		// the cursor never stops at a system that always runs
+		if cursor == s.cursor {
+			s.cursor.System += 1
+		}
+
+		return true
+	}
+
+	switch s.action {
+	case steppingStep:
+		if cursor != s.cursor {
+			return false
+		}
+
+		s.action = steppingWait
+		s.cursor.System += 1
+		s.atBreakpoint = false
+		return true
+
+	case steppingContinue:
+		if cursor.less(s.cursor) {
+			return false
+		}
+
+		// we do not stop at the breakpoint we are continuing from
+		if !s.atBreakpoint && s.matchesAny(s.breakpoints, scheduleId, system) {
+			s.action = steppingWait
+			s.cursor = cursor
+			s.cursorSystem = system.Name
+			s.atBreakpoint = true
+			return false
+		}
+
+		s.cursor = steppingCursor{Schedule: scheduleIdx, System: systemIdx + 1}
+		s.atBreakpoint = false
+		return true
+
+	default:
+		if cursor == s.cursor {
+			s.cursorSystem = system.Name
+		}
+
+		return false
+	}
+}
+
+// scheduleFinished is called by World.RunSchedule after all systems of a
+// schedule were executed. It moves the cursor to the next schedule if needed.
+func (s *Stepping) scheduleFinished(scheduleId ScheduleId, systemCount int) {
+	if !s.enabled || s.cursor.Schedule >= len(s.schedules) || s.schedules[s.cursor.Schedule] != scheduleId {
+		return
+	}
+
+	if s.cursor.System < systemCount {
+		return
+	}
+
+	s.cursor = steppingCursor{Schedule: s.cursor.Schedule + 1}
+	s.cursorSystem = ""
+
+	if s.cursor.Schedule >= len(s.schedules) {
+		// we've reached the end of the frame
+		s.cursor = steppingCursor{}
+
+		if s.action == steppingContinue {
+			s.action = steppingWait
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package byke
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepping(t *testing.T) {
+	var executed []string
+
+	record := func(name string) func() {
+		return func() { executed = append(executed, name) }
+	}
+
+	a, b, c := record("a"), record("b"), record("c")
+	input := record("input")
+
+	var app App
+	app.AddSystems(Update, System(a, b, c).Chain())
+	app.AddSystems(PreUpdate, input)
+
+	app.InsertResource(Stepping{})
+
+	stepping := app.World().RequireResourceOf[Stepping]()
+	stepping.AddSchedule(PreUpdate).AddSchedule(Update)
+	stepping.AlwaysRun(PreUpdate, input)
+	stepping.Enable()
+
+	runFrame := func() []string {
+		executed = nil
+		app.World().RunSchedule(Main)
+		return executed
+	}
+
+	// paused, only the always run system is executed
+	require.Equal(t, []string{"input"}, runFrame())
+
+	_, name, ok := stepping.Cursor()
+	require.True(t, ok)
+	require.Contains(t, name, "TestStepping")
+
+	stepping.StepSystem()
+	require.Equal(t, []string{"input", "a"}, runFrame())
+	require.Equal(t, []string{"input"}, runFrame())
+
+	stepping.StepSystem()
+	require.Equal(t, []string{"input", "b"}, runFrame())
+
+	// continue to the end of the frame
+	stepping.ContinueFrame()
+	require.Equal(t, []string{"input", "c"}, runFrame())
+	require.Equal(t, []string{"input"}, runFrame())
+
+	// continue stops at the breakpoint
+	stepping.SetBreakpoint(Update, b)
+	stepping.ContinueFrame()
+	require.Equal(t, []string{"input", "a"}, runFrame())
+
+	stepping.ContinueFrame()
+	require.Equal(t, []string{"input", "b", "c"}, runFrame())
+
+	stepping.ClearBreakpoint(Update, b)
+	stepping.Disable()
+	require.Equal(t, []string{"input", "a", "b", "c"}, runFrame())
+}
+
+func TestSteppingRunsInternalSystems(t *testing.T) {
+	var executed []string
+
+	var app App
+	app.AddSystems(Update, func() { executed = append(executed, "user") })
+	app.AddSystems(Update, System(func() { executed = append(executed, "internal") }).Internal())
+
+	app.InsertResource(Stepping{})
+
+	stepping := app.World().RequireResourceOf[Stepping]()
+	stepping.AddSchedule(Update)
+	stepping.Enable()
+
+	// internal systems are not paused
+	app.World().RunSchedule(Main)
+	require.Equal(t, []string{"internal"}, executed)
+}
+
+func TestSteppingUpdatesMessages(t *testing.T) {
+	type message struct{}
+
+	var app App
+	app.AddMessage[message]()
+
+	app.InsertResource(Stepping{})
+
+	stepping := app.World().RequireResourceOf[Stepping]()
+	stepping.AddSchedule(Last)
+	stepping.Enable()
+
+	messages := app.World().RequireResourceOf[Messages[message]]()
+	messages.Send(message{})
+
+	// the message buffers are still swapped while stepping
+	app.World().RunSchedule(Main)
+	app.World().RunSchedule(Main)
+
+	app.World().RunSystem(func(reader *MessageReader[message]) {
+		require.Empty(t, reader.Read())
+	})
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/oliverbestmann/byke/internal/refl"
 	"github.com/oliverbestmann/byke/internal/typedpool"
 	"github.com/oliverbestmann/byke/spoke"
-	"github.com/oliverbestmann/puffin-go"
 )
 
 var valueSlices = typedpool.New[[]reflect.Value]()
@@ -57,7 +56,7 @@ func (w *World) prepareSystemUncached(config systemConfig) *preparedSystem {
 		Name:         funcNameOf(config.SystemFunc),
 	}
 
-	defer puffin.NewScopeWithValue("byke.PrepareSystem", preparedSystem.Name).End()
+	defer w.newProfileScope("byke.PrepareSystem", preparedSystem.Name).End()
 
 	slog.Debug("Prepare system", slog.String("name", preparedSystem.Name), slog.Int("idx", len(w.systems)))
 
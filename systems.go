@@ -110,6 +110,9 @@ type systemConfig struct {
 	SystemSets set.Set[*SystemSet]
 
 	Predicates []AnySystem
+
+	// Internal systems are added by plugins to keep the world consistent
+	Internal bool
 }
 
 func (conf *systemConfig) MergeWith(other *systemConfig) *systemConfig {
@@ -121,6 +124,7 @@ func (conf *systemConfig) MergeWith(other *systemConfig) *systemConfig {
 	conf.After.InsertAll(other.After.Values())
 	conf.SystemSets.InsertAll(other.SystemSets.Values())
 	conf.Predicates = append(conf.Predicates, other.Predicates...)
+	conf.Internal = conf.Internal || other.Internal
 
 	return conf
 }
@@ -134,6 +138,8 @@ type Systems struct {
 	sets   set.Set[*SystemSet]
 
 	predicates []AnySystem
+
+	internal bool
 }
 
 // System wraps one or multiple systems (raw functions, other Systems) as a Systems instance.
@@ -176,6 +182,13 @@ func (s Systems) RunIf(predicate AnySystem) Systems {
 	return s
 }
 
+// Internal marks the systems as internal to a plugin. Internal systems keep the world
+// consistent, e.g. by updating message buffers, and are never paused by Stepping.
+func (s Systems) Internal() Systems {
+	s.internal = true
+	return s
+}
+
 // Chain chains the systems to run one after another.
 func (s Systems) Chain() Systems {
 	systems := s.asSystemConfigs()
@@ -201,6 +214,7 @@ func (s Systems) asSystemConfigs() []*systemConfig {
 		system.Before.InsertAll(s.before.Values())
 		system.SystemSets.InsertAll(s.sets.Values())
 		system.Predicates = append(system.Predicates, s.predicates...)
+		system.Internal = system.Internal || s.internal
 	}
 
 	return systems
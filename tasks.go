@@ -0,0 +1,170 @@
+package byke
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Tasks is a resource that runs background work in goroutines with bounded concurrency.
+// Use Tasks.Spawn to start a new Task.
+type Tasks struct {
+	limit chan struct{}
+}
+
+// NewTasks creates a new Tasks instance running at most concurrency tasks at the same time.
+func NewTasks(concurrency int) Tasks {
+	if concurrency <= 0 {
+		panic(fmt.Errorf("concurrency must be positive, got %d", concurrency))
+	}
+
+	return Tasks{limit: make(chan struct{}, concurrency)}
+}
+
+// Spawn starts the given function in a new goroutine. The function will not start
+// before a slot becomes available. The context passed to the function is cancelled
+// when the Task is cancelled.
+func (t *Tasks) Spawn[T any](fn func(ctx context.Context) (T, error)) *Task[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	task := &Task[T]{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(task.done)
+		defer cancel()
+
+		// wait for a free slot
+		select {
+		case t.limit <- struct{}{}:
+			defer func() { <-t.limit }()
+
+		case <-ctx.Done():
+			task.err = ctx.Err()
+			return
+		}
+
+		task.value, task.err = fn(ctx)
+
+		// fails if the task was cancelled in the meantime
+		task.state.CompareAndSwap(taskRunning, taskFinished)
+	}()
+
+	return task
+}
+
+const (
+	taskRunning int32 = iota
+	taskFinished
+	taskCancelled
+)
+
+// Task is a handle to a background task started with Tasks.Spawn.
+type Task[T any] struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// either taskRunning, taskFinished or taskCancelled. A task is either
+	// finished or cancelled, the state never changes after leaving taskRunning.
+	state atomic.Int32
+
+	value T
+	err   error
+}
+
+// Cancel cancels the context of the task. Cancelling a finished task has no effect.
+func (t *Task[T]) Cancel() {
+	t.state.CompareAndSwap(taskRunning, taskCancelled)
+	t.cancel()
+}
+
+// IsCancelled returns true, if Cancel was called before the task finished.
+func (t *Task[T]) IsCancelled() bool {
+	return t.state.Load() == taskCancelled
+}
+
+// IsFinished returns true, if the task has finished, either successfully or with an error.
+func (t *Task[T]) IsFinished() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Poll returns the result of the task, if the task has finished.
+func (t *Task[T]) Poll() (T, error, bool) {
+	if !t.IsFinished() {
+		var tZero T
+		return tZero, nil, false
+	}
+
+	return t.value, t.err, true
+}
+
+// Await blocks until the task has finished and returns its result.
+func (t *Task[T]) Await() (T, error) {
+	<-t.done
+	return t.value, t.err
+}
+
+// PendingTask holds a Task owned by an entity. Add the PluginTask for the same type
+// to receive the result of the task. The task is cancelled if the component is removed
+// from its entity, e.g. when the entity is despawned, or if it is replaced by
+// inserting another PendingTask of the same type.
+type PendingTask[T any] struct {
+	Component[PendingTask[T]]
+	Task *Task[T]
+}
+
+// TaskCallback receives the result of a finished PendingTask.
+// The PendingTask component is removed from the entity after the callback returned.
+type TaskCallback[T any] func(commands *Commands, entityId EntityId, value T, err error)
+
+// PluginTask polls all PendingTask components of type T in PreUpdate and
+// passes the results of finished tasks to the given callback.
+// Results of cancelled tasks are not passed to the callback.
+func PluginTask[T any](callback TaskCallback[T]) Plugin {
+	return func(app *App) {
+		ValidateComponent[PendingTask[T]]()
+
+		app.World().RegisterComponentHooks[PendingTask[T]]().
+			OnDiscard(func(world DeferredWorld, entity EntityRef, componentType *ComponentType) {
+				pending := entity.Get(componentType).(*PendingTask[T])
+				if pending.Task != nil {
+					pending.Task.Cancel()
+				}
+			})
+
+		app.AddSystems(PreUpdate, System(func(
+			commands *Commands,
+			query Query[struct {
+				EntityId
+				PendingTask PendingTask[T]
+			}],
+		) {
+			for item := range query.Items() {
+				task := item.PendingTask.Task
+				if task == nil {
+					continue
+				}
+
+				value, err, ok := task.Poll()
+				if !ok {
+					continue
+				}
+
+				commands.Entity(item.EntityId).Remove[PendingTask[T]]()
+
+				if task.IsCancelled() {
+					continue
+				}
+
+				callback(commands, item.EntityId, value, err)
+			}
+		}).Internal())
+	}
+}
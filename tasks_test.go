@@ -0,0 +1,184 @@
+package byke
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasks(t *testing.T) {
+	var app App
+
+	type result struct {
+		EntityId EntityId
+		Value    int
+	}
+
+	var results []result
+
+	app.AddPlugin(PluginTask[int](func(commands *Commands, entityId EntityId, value int, err error) {
+		require.NoError(t, err)
+		results = append(results, result{EntityId: entityId, Value: value})
+	}))
+
+	w := app.World()
+	tasks := w.RequireResourceOf[Tasks]()
+
+	task := tasks.Spawn(func(ctx context.Context) (int, error) { return 42, nil })
+	entityId := w.Spawn([]ErasedComponent{PendingTask[int]{Task: task}})
+
+	_, _ = task.Await()
+	w.RunSchedule(PreUpdate)
+
+	require.Equal(t, []result{{EntityId: entityId, Value: 42}}, results)
+
+	// the task component was removed
+	w.RunSystem(func(query Query[PendingTask[int]]) {
+		require.Equal(t, 0, query.Count())
+	})
+}
+
+func TestTasksCancelOnDespawn(t *testing.T) {
+	var app App
+
+	app.AddPlugin(PluginTask[int](func(*Commands, EntityId, int, error) {
+		require.Fail(t, "callback must not be called for a cancelled task")
+	}))
+
+	w := app.World()
+	tasks := w.RequireResourceOf[Tasks]()
+
+	task := tasks.Spawn(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	entityId := w.Spawn([]ErasedComponent{PendingTask[int]{Task: task}})
+	w.Despawn(entityId)
+
+	_, err := task.Await()
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTasksBoundedConcurrency(t *testing.T) {
+	tasks := NewTasks(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := tasks.Spawn(func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+
+	<-started
+
+	second := tasks.Spawn(func(ctx context.Context) (int, error) { return 2, nil })
+
+	// the second task can not run while the first one is still blocking the only slot
+	require.False(t, second.IsFinished())
+
+	second.Cancel()
+	_, err := second.Await()
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	value, err := first.Await()
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+}
+
+func TestTasksCancelOnReplace(t *testing.T) {
+	var app App
+
+	var values []int
+
+	app.AddPlugin(PluginTask[int](func(commands *Commands, entityId EntityId, value int, err error) {
+		require.NoError(t, err)
+		values = append(values, value)
+	}))
+
+	w := app.World()
+	tasks := w.RequireResourceOf[Tasks]()
+
+	first := tasks.Spawn(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	entityId := w.Spawn([]ErasedComponent{PendingTask[int]{Task: first}})
+
+	second := tasks.Spawn(func(ctx context.Context) (int, error) { return 2, nil })
+	_ = w.InsertComponents(entityId, PendingTask[int]{Task: second})
+
+	// replacing the component cancels the previous task
+	_, err := first.Await()
+	require.ErrorIs(t, err, context.Canceled)
+	require.True(t, first.IsCancelled())
+
+	_, _ = second.Await()
+	w.RunSchedule(PreUpdate)
+
+	require.Equal(t, []int{2}, values)
+}
+
+func TestTasksErrorWrappingCanceled(t *testing.T) {
+	var app App
+
+	var errs []error
+
+	app.AddPlugin(PluginTask[int](func(commands *Commands, entityId EntityId, value int, err error) {
+		errs = append(errs, err)
+	}))
+
+	w := app.World()
+	tasks := w.RequireResourceOf[Tasks]()
+
+	task := tasks.Spawn(func(ctx context.Context) (int, error) {
+		// a context owned by the task itself
+		inner, cancel := context.WithCancel(ctx)
+		cancel()
+
+		return 0, fmt.Errorf("request failed: %w", inner.Err())
+	})
+
+	w.Spawn([]ErasedComponent{PendingTask[int]{Task: task}})
+
+	_, _ = task.Await()
+	w.RunSchedule(PreUpdate)
+
+	// the task was not cancelled, so its error is passed to the callback
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], context.Canceled)
+}
+
+func TestTasksCancelAndCompletionAreExclusive(t *testing.T) {
+	tasks := NewTasks(1)
+
+	// cancelling a finished task has no effect
+	finished := tasks.Spawn(func(ctx context.Context) (int, error) { return 1, nil })
+	_, _ = finished.Await()
+
+	finished.Cancel()
+	require.False(t, finished.IsCancelled())
+
+	// a task cancelled while running stays cancelled, even if it returns a result
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	running := tasks.Spawn(func(ctx context.Context) (int, error) {
+		close(started)
+		<-unblock
+		return 2, nil
+	})
+
+	<-started
+	running.Cancel()
+	close(unblock)
+
+	value, err := running.Await()
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+	require.True(t, running.IsCancelled())
+}
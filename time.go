@@ -17,6 +17,9 @@ type FixedTime struct {
 	StepInterval time.Duration
 	overstep     time.Duration
 
+	// number of fixed steps run so far
+	steps int
+
 	DeltaSecs float32
 }
 
@@ -35,13 +38,25 @@ type VirtualTime struct {
 	Frames int
 }
 
-func updateVirtualTime(v *VirtualTime, lastTime *Local[time.Time]) {
+// FakeClock replaces the system clock used to update VirtualTime.
+// If the resource exists, each frame advances Now by exactly Step,
+// which makes the progression of time deterministic, e.g. in tests.
+type FakeClock struct {
+	Now  time.Time
+	Step time.Duration
+}
+
+func updateVirtualTime(v *VirtualTime, lastTime *Local[time.Time], fakeClock ResOption[FakeClock]) {
 	v.Frames += 1
 
-	// if in testing, we update time by exactly 1/60th of a second
-	incrementTime(time.Second / 60)
+	var now time.Time
 
-	now := timeNow()
+	if clock := fakeClock.Value; clock != nil {
+		clock.Now = clock.Now.Add(clock.Step)
+		now = clock.Now
+	} else {
+		now = time.Now()
+	}
 
 	if lastTime.Value.IsZero() {
 		lastTime.Value = now
@@ -47,3 +47,106 @@ func TestTriggers(t *testing.T) {
 		require.False(t, exists)
 	})
 }
+
+type Ping struct {
+	EventTarget
+}
+
+func TestObserverIndex(t *testing.T) {
+	w := NewWorld()
+
+	var pinged []EntityId
+	pingSystem := func(trigger On[Ping]) {
+		pinged = append(pinged, trigger.Event.TargetEntityId())
+	}
+
+	var globalPings int
+	w.AddObserver(NewObserver(func(On[Ping]) { globalPings += 1 }))
+
+	targets := w.SpawnBatch(3, func(int) []ErasedComponent { return []ErasedComponent{Object{}} })
+
+	var observers []EntityId
+	for _, targetId := range targets {
+		observers = append(observers, w.AddObserver(NewObserver(pingSystem).WatchEntity(targetId)))
+	}
+
+	// one observer watching two entities
+	shared := w.AddObserver(NewObserver(pingSystem).WatchEntity(targets[1]).WatchEntity(targets[2]))
+
+	w.TriggerObserver(Ping{EventTarget: EventTarget(targets[1])})
+	require.Equal(t, []EntityId{targets[1], targets[1]}, pinged)
+
+	// global observers only receive untargeted events
+	require.Zero(t, globalPings)
+	w.TriggerObserver(Ping{})
+	require.Equal(t, 1, globalPings)
+
+	// observers of a despawned entity are cleaned up
+	w.Despawn(targets[1])
+	_, ok := w.Entity(observers[1])
+	require.False(t, ok)
+
+	// an observer still watching another entity is kept
+	_, ok = w.Entity(shared)
+	require.True(t, ok)
+
+	pinged = nil
+	w.TriggerObserver(Ping{EventTarget: EventTarget(targets[2])})
+	require.Equal(t, []EntityId{targets[2], targets[2]}, pinged)
+
+	// removing the observer component removes the observer from the index
+	w.Despawn(observers[2])
+	pinged = nil
+	w.TriggerObserver(Ping{EventTarget: EventTarget(targets[2])})
+	require.Equal(t, []EntityId{targets[2]}, pinged)
+
+	w.Despawn(targets[2])
+	_, ok = w.Entity(shared)
+	require.False(t, ok)
+	require.Equal(t, map[EntityId][]EntityId{targets[0]: {observers[0]}}, w.observers.byTarget)
+}
+
+func TestObserverKeepsHooks(t *testing.T) {
+	w := NewWorld()
+
+	// the observer index must not occupy the hooks of the Observer component
+	var inserted int
+	w.RegisterComponentHooks[Observer]().
+		OnInsert(func(world DeferredWorld, entity EntityRef, componentType *ComponentType) {
+			inserted++
+		})
+
+	targets := w.SpawnBatch(2, func(int) []ErasedComponent { return []ErasedComponent{Object{}} })
+
+	var pings int
+	observerId := w.AddObserver(NewObserver(func(On[Ping]) { pings++ }).
+		WatchEntity(targets[0]).
+		WatchEntity(targets[1]))
+
+	require.Equal(t, 1, inserted)
+
+	var changed bool
+	checkChanged := func(q Query[struct {
+		_ Changed[Observer]
+		EntityId
+	}]) {
+		_, changed = q.Get(observerId)
+	}
+
+	w.RunSystem(checkChanged)
+	w.RunSystem(checkChanged)
+	require.False(t, changed)
+
+	w.TriggerObserver(Ping{EventTarget: EventTarget(targets[1])})
+	require.Equal(t, 1, pings)
+
+	// unwatching a despawned entity replaces the observer component
+	w.Despawn(targets[0])
+	require.Equal(t, 2, inserted)
+
+	w.RunSystem(checkChanged)
+	require.True(t, changed)
+
+	w.TriggerObserver(Ping{EventTarget: EventTarget(targets[1])})
+	require.Equal(t, 2, pings)
+}
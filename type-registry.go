@@ -0,0 +1,90 @@
+package byke
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/oliverbestmann/byke/spoke"
+)
+
+// TypeRegistry resolves component and event types by their name. It is used to
+// work with values whose type is not known at compile time, e.g. values decoded
+// from a remote connection.
+//
+// Every component type used with the World is known to the registry. Component types
+// that were never used can be made known using RegisterComponent. Event types
+// must always be registered explicitly using RegisterEvent.
+//
+// Use World.TypeRegistry to access the registry of a World.
+type TypeRegistry struct {
+	events map[string]reflect.Type
+}
+
+// RegisterComponent makes the component type C known to the registry.
+func (r *TypeRegistry) RegisterComponent[C IsComponent[C]]() {
+	_ = spoke.ComponentTypeOf[C]()
+}
+
+// RegisterEvent makes the event type E known to the registry.
+func (r *TypeRegistry) RegisterEvent[E Event]() {
+	if r.events == nil {
+		r.events = map[string]reflect.Type{}
+	}
+
+	ty := reflect.TypeFor[E]()
+	r.events[ty.String()] = ty
+}
+
+// ComponentTypes returns all known component types.
+func (r *TypeRegistry) ComponentTypes() []*ComponentType {
+	return spoke.ComponentTypes()
+}
+
+// ComponentType looks up a component type by its name, e.g. "byke.Name".
+func (r *TypeRegistry) ComponentType(name string) (*ComponentType, bool) {
+	return componentTypeByName(name)
+}
+
+// componentTypeByName looks up a component type by its name. Component types
+// are known globally, no TypeRegistry is required to look them up.
+func componentTypeByName(name string) (*ComponentType, bool) {
+	for _, ty := range spoke.ComponentTypes() {
+		if ty.Name == name {
+			return ty, true
+		}
+	}
+
+	return nil, false
+}
+
+// EventTypes returns all registered event types, sorted by name.
+func (r *TypeRegistry) EventTypes() []reflect.Type {
+	var types []reflect.Type
+	for _, ty := range r.events {
+		types = append(types, ty)
+	}
+
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return types
+}
+
+// EventType looks up a registered event type by its name.
+func (r *TypeRegistry) EventType(name string) (reflect.Type, bool) {
+	ty, ok := r.events[name]
+	return ty, ok
+}
+
+// TypeRegistry returns the TypeRegistry of this world. The registry
+// is also available as a resource.
+func (w *World) TypeRegistry() *TypeRegistry {
+	if registry, ok := w.ResourceOf[TypeRegistry](); ok {
+		return registry
+	}
+
+	w.InsertResource(TypeRegistry{})
+	return w.RequireResourceOf[TypeRegistry]()
+}
@@ -2,7 +2,6 @@ package byke
 
 import (
 	"fmt"
-	"reflect"
 	"sync"
 )
 
@@ -64,17 +63,17 @@ func validateComponent(c ErasedComponent) {
 	if parent, ok := componentType.New().(isRelationshipTargetType); ok {
 		// check if the child type points to us
 		childType := parent.RelationshipType()
-		instance := reflect.New(childType.Type).Elem().Interface()
+		instance := childType.New()
 
-		child, ok := instance.(isRelationshipComponent)
+		targetType, _, ok := relationshipTargetsOf(instance)
 		if !ok {
 			panic(fmt.Sprintf(
-				"relationship target of %s must point to a component embedding byke.Relationship",
+				"relationship target of %s must point to a component embedding byke.Relationship or byke.ManyRelationship",
 				componentType,
 			))
 		}
 
-		if child.RelationshipTargetType() != componentType {
+		if targetType != componentType {
 			panic(fmt.Sprintf(
 				"relationship target of %s must point to %s",
 				childType, componentType,
@@ -82,9 +81,8 @@ func validateComponent(c ErasedComponent) {
 		}
 	}
 
-	if child, ok := componentType.New().(isRelationshipComponent); ok {
-		// check if the child type points to us
-		parentType := child.RelationshipTargetType()
+	if parentType, _, ok := relationshipTargetsOf(componentType.New()); ok {
+		// check if the parent type points to us
 
 		parentComponent := parentType.New()
 		parent, ok := parentComponent.(isRelationshipTargetType)
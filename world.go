@@ -1,18 +1,23 @@
 package byke
 
 import (
+	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
 	"reflect"
+	"slices"
 	"sync/atomic"
 
 	"github.com/oliverbestmann/byke/internal/set"
 	"github.com/oliverbestmann/byke/spoke"
-	"github.com/oliverbestmann/puffin-go"
 )
 
 const NoEntityId = EntityId(0)
 
+// ErrNoSuchEntity is returned when accessing an entity that does not exist.
+var ErrNoSuchEntity = errors.New("entity does not exist")
+
 type AnyPtr = any
 
 // World holds all entities and resources, schedules, systems, etc.
@@ -31,6 +36,14 @@ type World struct {
 	activeQueries atomic.Int32
 
 	commands CommandQueue
+
+	observers *observerIndex
+
+	// profiler records the scopes of this world, if enabled using PluginProfiler
+	profiler *Profiler
+
+	// number of commands applied so far
+	appliedCommands uint64
 }
 
 // NewWorld creates a new empty world.
@@ -46,11 +59,13 @@ func NewWorld() *World {
 		forwardToNewState[messageWriterT],
 		forwardToNewState[messageReaderT],
 		forwardToNewState[singleT],
+		forwardToNewState[queryByIndexT],
 		forwardToNewState[inT],
 		forwardToNewState[onT],
 		forwardToNewState[resT],
-		forwardToNewState[resOptionT],
+		ForwardToNewStateOnPointer[resOptionT],
 		forwardToNewState[removedComponentsT],
+		forwardToNewState[hierarchyT],
 	}
 
 	return &World{
@@ -60,6 +75,7 @@ func NewWorld() *World {
 		systems:           map[SystemId]*preparedSystem{},
 		makeSystemParams:  defaultMakeSystemParams,
 		currentTick:       1,
+		observers:         newObserverIndex(),
 	}
 }
 
@@ -146,7 +162,7 @@ func (w *World) runSystem(system *preparedSystem, ctx SystemContext) any {
 }
 
 func (w *World) runSystemWithoutApplyingCommands(system *preparedSystem, ctx SystemContext) any {
-	defer puffin.NewScopeWithValue("byke.RunSystem", system.Name).End()
+	defer w.newProfileScope("byke.RunSystem", system.Name).End()
 
 	for _, predicate := range system.Predicates {
 		result := w.runSystemWithoutApplyingCommands(predicate, SystemContext{})
@@ -193,7 +209,7 @@ func (w *World) RunSchedule(scheduleId ScheduleId) {
 		return
 	}
 
-	defer puffin.NewScopeWithValue("RunSchedule", scheduleId.String()).End()
+	defer w.newProfileScope("RunSchedule", scheduleId.String()).End()
 
 	// all added commands should be handled already
 	checkpoint := w.commands.Checkpoint()
@@ -215,9 +231,20 @@ func (w *World) RunSchedule(scheduleId ScheduleId) {
 		defer timings.MeasureSchedule(scheduleId).Stop()
 	}
 
-	for _, system := range schedule.Systems() {
+	stepping, _ := w.ResourceOf[Stepping]()
+
+	systems := schedule.Systems()
+	for idx, system := range systems {
+		if stepping != nil && !stepping.shouldRun(scheduleId, idx, system) {
+			continue
+		}
+
 		w.runSystem(system, SystemContext{})
 	}
+
+	if stepping != nil {
+		stepping.scheduleFinished(scheduleId, len(systems))
+	}
 }
 
 func assertIsEmpty(slice []Command) {
@@ -285,9 +312,105 @@ func (w *World) spawnWithEntityId(entityId EntityId, components []ErasedComponen
 	return entityId
 }
 
+// SpawnBatch spawns n entities. The components of the i-th entity are
+// provided by calling makeComponents(i). Memory for all entities is reserved
+// up front, which makes this much faster than calling Spawn n times.
+func (w *World) SpawnBatch(n int, makeComponents func(i int) []ErasedComponent) []EntityId {
+	entityIds := make([]EntityId, n)
+	components := make([][]ErasedComponent, n)
+
+	for idx := range n {
+		entityIds[idx] = w.reserveEntityId()
+		components[idx] = makeComponents(idx)
+	}
+
+	w.spawnBatchWithEntityIds(entityIds, components)
+
+	return entityIds
+}
+
+func (w *World) spawnBatchWithEntityIds(entityIds []EntityId, components [][]ErasedComponent) {
+	prepared := make([][]ErasedComponent, len(entityIds))
+	spawnChildren := make([][]*spawnChildComponent, len(entityIds))
+
+	for idx, entityId := range entityIds {
+		prepared[idx], spawnChildren[idx] = w.prepareComponents(entityId, components[idx])
+	}
+
+	w.storage.SpawnBatch(w.currentTick, entityIds, prepared)
+
+	for idx, entityId := range entityIds {
+		w.onComponentsInsert(entityId, prepared[idx])
+
+		// now spawn all childrens as necessary
+		for _, spawnChild := range spawnChildren[idx] {
+			components := append(spawnChild.Components, ChildOf{Parent: entityId})
+			w.spawnWithEntityId(w.reserveEntityId(), components)
+		}
+	}
+}
+
+// InsertBatch inserts the same component into all the given entities.
+// Memory in the target archetypes is reserved up front.
+func (w *World) InsertBatch(entityIds []EntityId, component ErasedComponent) {
+	w.storage.ReserveInsert(entityIds, component.ComponentType())
+
+	for _, entityId := range entityIds {
+		w.insertComponents(entityId, []ErasedComponent{component})
+	}
+}
+
+// Entity returns a reference to the entity with the given id.
+func (w *World) Entity(entityId EntityId) (EntityRef, bool) {
+	return w.storage.Get(entityId)
+}
+
+// Entities returns all entities in the world, including disabled ones.
+func (w *World) Entities() iter.Seq[EntityRef] {
+	type entityItem struct {
+		Entity EntityRef
+		_      Allow[Disabled]
+	}
+
+	return func(yield func(EntityRef) bool) {
+		query := w.Query[entityItem]()
+		for item := range query.Items() {
+			if !yield(item.Entity) {
+				return
+			}
+		}
+	}
+}
+
+// InsertComponents inserts the given components into an existing entity.
+// Existing components of the same type are replaced.
+func (w *World) InsertComponents(entityId EntityId, components ...ErasedComponent) error {
+	if _, ok := w.storage.Get(entityId); !ok {
+		return fmt.Errorf("insert into %s: %w", entityId, ErrNoSuchEntity)
+	}
+
+	w.insertComponents(entityId, components)
+	return nil
+}
+
+// RemoveComponent removes the component of the given type from an entity.
+// Removing a component the entity does not have is a no-op.
+func (w *World) RemoveComponent(entityId EntityId, componentType *ComponentType) error {
+	if _, ok := w.storage.Get(entityId); !ok {
+		return fmt.Errorf("remove from %s: %w", entityId, ErrNoSuchEntity)
+	}
+
+	w.removeComponent(entityId, componentType)
+	return nil
+}
+
 func (w *World) insertComponents(entityId EntityId, components []ErasedComponent) {
 	components, spawnChildren := w.prepareComponents(entityId, components)
 
+	// a relationship component that gets replaced must first be
+	// removed from its previous relationship target
+	w.unlinkReplacedRelationships(entityId, components)
+
 	w.storage.InsertComponents(w.currentTick, entityId, components)
 	w.onComponentsInsert(entityId, components)
 
@@ -345,6 +468,34 @@ func (w *World) prepareComponents(entityId EntityId, components []ErasedComponen
 	return
 }
 
+func (w *World) unlinkReplacedRelationships(entityId EntityId, components []ErasedComponent) {
+	entity, ok := w.storage.Get(entityId)
+	if !ok {
+		return
+	}
+
+	for _, component := range components {
+		_, targetIds, ok := relationshipTargetsOf(component)
+		if !ok {
+			continue
+		}
+
+		previous := entity.Get(component.ComponentType())
+		if previous == nil {
+			continue
+		}
+
+		// targets that are still in the new component keep the position
+		// of the entity within their relationship target
+		previousType, previousIds, _ := relationshipTargetsOf(previous)
+		for _, previousId := range previousIds {
+			if !slices.Contains(targetIds, previousId) {
+				w.unlinkRelationshipTarget(entityId, previousId, previousType)
+			}
+		}
+	}
+}
+
 func (w *World) onComponentsInsert(id EntityId, components []ErasedComponent) {
 	for _, component := range components {
 		w.onComponentInsert(id, component)
@@ -352,101 +503,194 @@ func (w *World) onComponentsInsert(id EntityId, components []ErasedComponent) {
 }
 
 func (w *World) onComponentInsert(entityId EntityId, component ErasedComponent) {
-	if targetComponent, targetId, targetType, ok := w.relationshipTargetComponentOf(component); ok {
-		if targetComponent == nil {
-			// create a new instance of the component
-			targetComponent = targetType.New().(isRelationshipTargetType)
-		} else {
-			// create a copy of the component
-			targetComponent = copyComponent(targetComponent).(isRelationshipTargetType)
-		}
-
-		// add the child to the relationship target
-		targetComponent.addChild(entityId)
+	switch observer := component.(type) {
+	case Observer:
+		w.observers.insert(entityId, &observer)
+	case *Observer:
+		w.observers.insert(entityId, observer)
+	}
 
-		// and replace its value by inserting it again
-		w.storage.InsertComponent(w.currentTick, targetId, targetComponent)
+	if targetType, targetIds, ok := relationshipTargetsOf(component); ok {
+		for _, targetId := range targetIds {
+			w.linkRelationshipTarget(entityId, targetId, targetType)
+		}
 	}
 }
 
 func (w *World) onComponentRemoved(entityId EntityId, component ErasedComponent) {
-	w.removeEntityFromParentComponentOf(entityId, component)
+	if component.ComponentType() == observerComponentType {
+		w.observers.remove(entityId)
+	}
+
+	if targetType, targetIds, ok := relationshipTargetsOf(component); ok {
+		for _, targetId := range targetIds {
+			w.unlinkRelationshipTarget(entityId, targetId, targetType)
+		}
+	}
 
 	if registry, ok := w.ResourceOf[removedComponentsRegistry](); ok {
 		registry.ComponentRemoved(entityId, component.ComponentType())
 	}
 }
 
-func (w *World) removeEntityFromParentComponentOf(entityId EntityId, component ErasedComponent) {
-	if targetComponent, targetId, _, ok := w.relationshipTargetComponentOf(component); ok && targetComponent != nil {
+// linkRelationshipTarget adds the source entity to the relationship target component of type
+// targetType on the target entity. The component is created if it does not yet exist.
+// Targets that do not exist, e.g. after they were despawned using DespawnKeep, are skipped.
+func (w *World) linkRelationshipTarget(sourceId, targetId EntityId, targetType *spoke.ComponentType) {
+	target, ok := w.storage.Get(targetId)
+	if !ok {
+		slog.Warn(
+			"cannot link relationship: target entity does not exist",
+			slog.Any("sourceId", sourceId),
+			slog.Any("targetId", targetId),
+		)
 
-		children := targetComponent.Children()
+		return
+	}
 
-		if len(children) == 1 && children[0] == entityId {
-			// would need to remove the last element.
-			// in that case, we can just remove the component itself
-			w.storage.RemoveComponent(w.currentTick, targetId, targetComponent.ComponentType())
-		} else {
-			// create a copy of the component without the child
-			targetComponent = copyComponent(targetComponent).(isRelationshipTargetType)
-			targetComponent.removeChild(entityId)
+	var targetComponent isRelationshipTargetType
 
-			// and replace its value by inserting it again
-			w.storage.InsertComponent(w.currentTick, targetId, targetComponent)
-		}
+	if existing := target.Get(targetType); existing != nil {
+		// create a copy of the component
+		targetComponent = copyComponent(existing).(isRelationshipTargetType)
+	} else {
+		// create a new instance of the component
+		targetComponent = targetType.New().(isRelationshipTargetType)
 	}
+
+	// add the child to the relationship target
+	targetComponent.addChild(sourceId)
+
+	// and replace its value by inserting it again
+	w.storage.InsertComponent(w.currentTick, targetId, targetComponent)
 }
 
-func (w *World) relationshipTargetComponentOf(component ErasedComponent) (isRelationshipTargetType, EntityId, *spoke.ComponentType, bool) {
-	child, ok := component.(isRelationshipComponent)
+// unlinkRelationshipTarget removes the source entity from the relationship target
+// component of type targetType on the target entity.
+func (w *World) unlinkRelationshipTarget(sourceId, targetId EntityId, targetType *spoke.ComponentType) {
+	target, ok := w.storage.Get(targetId)
 	if !ok {
-		return nil, 0, nil, false
+		// the target might have been despawned using DespawnKeep
+		return
 	}
 
-	parentId := child.RelationshipEntityId()
+	targetComponent, ok := target.Get(targetType).(isRelationshipTargetType)
+	if !ok {
+		return
+	}
+
+	children := targetComponent.Children()
+
+	switch {
+	case len(children) == 1 && children[0] == sourceId:
+		// would need to remove the last element.
+		// in that case, we can just remove the component itself
+		w.storage.RemoveComponent(w.currentTick, targetId, targetType)
+
+	case slices.Contains(children, sourceId):
+		// create a copy of the component without the child
+		targetComponent = copyComponent(targetComponent).(isRelationshipTargetType)
+		targetComponent.removeChild(sourceId)
+
+		// and replace its value by inserting it again
+		w.storage.InsertComponent(w.currentTick, targetId, targetComponent)
+	}
+}
 
-	parent, ok := w.storage.Get(parentId)
+// unlinkRelationshipSource removes the target entity from the relationship component
+// of type sourceType on the source entity. The relationship component is removed
+// if the target was its last target.
+func (w *World) unlinkRelationshipSource(sourceId, targetId EntityId, sourceType *spoke.ComponentType) {
+	source, ok := w.storage.Get(sourceId)
 	if !ok {
-		panic(fmt.Sprintf("parent entity %s does not exist", parentId))
+		return
 	}
 
-	parentType := child.RelationshipTargetType()
-	parentComponentValue := parent.Get(parentType)
-	if parentComponentValue != nil {
-		return parentComponentValue.(isRelationshipTargetType), parentId, nil, true
+	relationship, ok := source.Get(sourceType).(isManyRelationshipComponent)
+	if !ok || len(relationship.RelationshipEntityIds()) <= 1 {
+		// a relationship with just one target can only be removed completely
+		w.removeComponent(sourceId, sourceType)
+		return
 	}
 
-	// there is no component in the parent
-	return nil, parentId, parentType, true
+	updated := copyComponent(relationship)
+	updated.(isRemoveRelationshipTarget).removeTarget(targetId)
+
+	w.storage.InsertComponent(w.currentTick, sourceId, updated)
 }
 
 // Despawn recursively despawns the given entity following Children relations.
+// A warning is logged if the entity does not exist. Use TryDespawn to handle this case yourself.
 func (w *World) Despawn(entityId EntityId) {
+	if err := w.TryDespawn(entityId); err != nil {
+		slog.Warn("cannot despawn entity", slog.Any("err", err))
+	}
+}
+
+// TryDespawn recursively despawns the given entity following Children relations.
+// Returns an error wrapping ErrNoSuchEntity if the entity does not exist.
+func (w *World) TryDespawn(entityId EntityId) error {
+	if _, ok := w.storage.Get(entityId); !ok {
+		return fmt.Errorf("despawn %s: %w", entityId, ErrNoSuchEntity)
+	}
+
 	queue := []EntityId{entityId}
 
+	var queued set.Set[EntityId]
+	queued.Insert(entityId)
+
 	for idx := 0; idx < len(queue); idx++ {
 		entityId = queue[idx]
 
 		entity, ok := w.storage.Get(entityId)
 		if !ok {
 			slog.Warn(
-				"cannot despawn entity: entity does not exist",
+				"cannot despawn child entity: entity does not exist",
 				slog.Any("entityId", entityId),
 			)
 
-			fmt.Printf("[warn] cannot despawn entity %d: does not exist\n", entityId)
 			continue
 		}
 
+		type unlinkSources struct {
+			SourceIds  []EntityId
+			SourceType *spoke.ComponentType
+		}
+
+		var unlink []unlinkSources
+
 		// update relationships
 		for _, component := range entity.Components() {
 			w.onComponentRemoved(entityId, component)
 
-			// despawn child entities too
-			if parentComponent, ok := component.(isRelationshipTargetType); ok {
-				for _, entityId := range parentComponent.Children() {
-					queue = append(queue, entityId)
+			parentComponent, ok := component.(isRelationshipTargetType)
+			if !ok {
+				continue
+			}
+
+			switch parentComponent.DespawnPolicy() {
+			case DespawnCascade:
+				// despawn child entities too. With many-to-many relationships,
+				// an entity might be reachable from multiple despawned entities
+				for _, childId := range parentComponent.Children() {
+					if queued.Insert(childId) {
+						queue = append(queue, childId)
+					}
 				}
+
+			case DespawnUnlink:
+				// unlink after we are done with the components of this entity,
+				// as unlinking modifies the component we are looking at
+				unlink = append(unlink, unlinkSources{
+					SourceIds:  slices.Clone(parentComponent.Children()),
+					SourceType: parentComponent.RelationshipType(),
+				})
+			}
+		}
+
+		for _, u := range unlink {
+			for _, sourceId := range u.SourceIds {
+				w.unlinkRelationshipSource(sourceId, entityId, u.SourceType)
 			}
 		}
 	}
@@ -454,6 +698,40 @@ func (w *World) Despawn(entityId EntityId) {
 	for _, entityId := range queue {
 		w.storage.Despawn(entityId)
 	}
+
+	for _, entityId := range queue {
+		w.unwatchDespawnedEntity(entityId)
+	}
+
+	return nil
+}
+
+// DespawnOnly despawns the given entity, but not its children. The children
+// are moved to the parent of the entity. If the entity has no parent,
+// the children become root entities.
+func (w *World) DespawnOnly(entityId EntityId) {
+	hierarchy := w.Hierarchy()
+
+	parentId, hasParent := hierarchy.Parent(entityId)
+
+	for _, childId := range slices.Clone(hierarchy.Children(entityId)) {
+		if hasParent {
+			w.SetParent(childId, parentId)
+		} else {
+			w.RemoveParent(childId)
+		}
+	}
+
+	// no children left, so this only despawns the entity itself
+	w.Despawn(entityId)
+}
+
+// DespawnDescendants recursively despawns all children of
+// the given entity, but keeps the entity itself.
+func (w *World) DespawnDescendants(entityId EntityId) {
+	for _, childId := range slices.Clone(w.Hierarchy().Children(entityId)) {
+		w.Despawn(childId)
+	}
 }
 
 func (w *World) Query[T any]() Query[T] {
@@ -517,9 +795,11 @@ func (w *World) applyCommands(checkpoint Checkpoint) {
 
 	commands := w.commands.DrainAt(checkpoint)
 	if len(commands) > 0 {
-		defer puffin.NewScope("byke.FlushCommands").End()
+		defer w.newProfileScope("byke.FlushCommands", "").End()
 	}
 
+	w.appliedCommands += uint64(len(commands))
+
 	for _, command := range commands {
 		command.Apply(w)
 	}
@@ -536,7 +816,6 @@ type triggerObserverIn struct {
 
 func triggerObserverSystem(
 	w *World,
-	observers Query[*Observer],
 	in In[triggerObserverIn],
 ) {
 	params := &in.Value
@@ -548,23 +827,23 @@ func triggerObserverSystem(
 		}
 	}
 
-	checkpoint := w.commands.Checkpoint()
+	disabledType := spoke.ComponentTypeOf[Disabled]()
 
-	for observer := range observers.Items() {
-		if !observer.ObservesType(params.ObserverType) {
-			continue
-		}
+	checkpoint := w.commands.Checkpoint()
 
-		if targetId == NoEntityId && observer.IsScoped() {
+	for _, observerId := range w.observers.observersOf(params.ObserverType, targetId) {
+		entry, ok := w.observers.byObserver[observerId]
+		if !ok {
+			// observer was removed by a previous observer
 			continue
 		}
 
-		if targetId != NoEntityId && !observer.Observes(targetId) {
+		if w.storage.HasComponent(observerId, disabledType) {
 			continue
 		}
 
 		// we found a match, trigger the observer
-		w.runSystemWithoutApplyingCommands(observer.system, SystemContext{
+		w.runSystemWithoutApplyingCommands(entry.System, SystemContext{
 			Trigger: systemTrigger{
 				EventValue: params.EventValue,
 			},
@@ -4,6 +4,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/oliverbestmann/byke/spoke"
 	"github.com/stretchr/testify/require"
 )
 
@@ -446,3 +447,355 @@ func BenchmarkWorld_RunSystem(b *testing.B) {
 		w.RunSchedule(schedule)
 	}
 }
+
+type Selected struct {
+	SparseComponent[Selected]
+}
+
+var _ = ValidateComponent[Selected]()
+
+func TestSparseComponents(t *testing.T) {
+	w := buildSimpleWorld()
+
+	w.RunSystem(func(commands *Commands, q Query[struct {
+		EntityId
+		Name Name
+	}]) {
+		for item := range q.Items() {
+			if item.Name.Name == "Tree" {
+				commands.Entity(item.EntityId).Insert(Selected{})
+			}
+		}
+	})
+
+	w.RunSystem(func(q Query[struct {
+		Name     Name
+		Position *Position
+		_        With[Selected]
+	}]) {
+		item := q.MustFirst()
+		require.Equal(t, 1, q.Count())
+		require.Equal(t, Named("Tree"), item.Name)
+		require.Equal(t, 2, item.Position.X)
+	})
+
+	w.RunSystem(func(q Query[struct {
+		Selected Has[Selected]
+		_        Without[Enemy]
+	}]) {
+		var selected int
+		for item := range q.Items() {
+			if item.Selected.Exists() {
+				selected += 1
+			}
+		}
+
+		require.Equal(t, 2, q.Count())
+		require.Equal(t, 1, selected)
+	})
+
+	w.RunSystem(func(commands *Commands, q Query[struct {
+		EntityId
+		_ With[Selected]
+	}]) {
+		commands.Entity(q.MustFirst().EntityId).Remove[Selected]()
+	})
+
+	w.RunSystem(func(q Query[Selected]) {
+		require.Equal(t, 0, q.Count())
+	})
+}
+
+func TestArchetypeCompaction(t *testing.T) {
+	var app App
+
+	app.InsertResource(ArchetypeCompaction{})
+
+	var positions []Position
+	collectSystem := func(q Query[Position]) {
+		positions = slices.Collect(q.Items())
+	}
+
+	w := app.World()
+	entityId := w.Spawn([]ErasedComponent{Position{X: 1}})
+
+	// prepares the system and caches its query
+	w.RunSystem(collectSystem)
+	require.Equal(t, []Position{{X: 1}}, positions)
+
+	w.Despawn(entityId)
+
+	w.RunSchedule(Last)
+
+	compaction := w.RequireResourceOf[ArchetypeCompaction]()
+	require.Equal(t, 1, compaction.Runs)
+	require.Equal(t, 1, compaction.Reclaimed.Archetypes)
+
+	// nothing to do this time
+	w.RunSchedule(Last)
+	require.Equal(t, 1, compaction.Runs)
+
+	// the archetype is created again and still matched by the cached query
+	w.Spawn([]ErasedComponent{Position{X: 2}})
+	w.RunSystem(collectSystem)
+	require.Equal(t, []Position{{X: 2}}, positions)
+}
+
+func TestSpawnBatch(t *testing.T) {
+	w := NewWorld()
+
+	entityIds := w.SpawnBatch(100, func(i int) []ErasedComponent {
+		return []ErasedComponent{Position{X: i}}
+	})
+
+	require.Len(t, entityIds, 100)
+
+	w.RunSystem(func(commands *Commands, q Query[Position]) {
+		require.Equal(t, 100, q.Count())
+
+		commands.SpawnBatch(10, func(i int) []ErasedComponent {
+			return []ErasedComponent{Position{X: i}, Velocity{Y: i}}
+		})
+
+		commands.InsertBatch(entityIds[:50], Velocity{X: 1})
+	})
+
+	w.RunSystem(func(q Query[struct {
+		Position Position
+		Velocity Velocity
+	}]) {
+		require.Equal(t, 60, q.Count())
+
+		for item := range q.Items() {
+			if item.Velocity.X == 1 {
+				require.Less(t, item.Position.X, 50)
+			}
+		}
+	})
+}
+
+func TestDisabled(t *testing.T) {
+	w := NewWorld()
+
+	parent := w.Spawn([]ErasedComponent{
+		Position{X: 1},
+		SpawnChild(Position{X: 2}),
+	})
+
+	w.Spawn([]ErasedComponent{Position{X: 3}})
+
+	w.RunSystem(func(q Query[Position]) {
+		require.Equal(t, 3, q.Count())
+	})
+
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(parent).DisableHierarchy()
+	})
+
+	w.RunSystem(func(
+		enabled Query[Position],
+		all Query[struct {
+			Position Position
+			_        Allow[Disabled]
+		}],
+		disabled Query[struct {
+			Position Position
+			_        With[Disabled]
+		}],
+	) {
+		require.Equal(t, 1, enabled.Count())
+		require.Equal(t, 3, all.Count())
+		require.Equal(t, 2, disabled.Count())
+	})
+
+	dynamic := w.DynamicQuery(spoke.ComponentTypeOf[Position]())
+	require.Len(t, slices.Collect(dynamic.Entities()), 1)
+
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(parent).EnableHierarchy()
+	})
+
+	w.RunSystem(func(q Query[Position]) {
+		require.Equal(t, 3, q.Count())
+	})
+
+	require.Len(t, slices.Collect(dynamic.Entities()), 3)
+}
+
+func TestHierarchy(t *testing.T) {
+	w := NewWorld()
+
+	root := w.Spawn([]ErasedComponent{Named("Root")})
+	a := w.Spawn([]ErasedComponent{Named("A"), ChildOf{Parent: root}})
+	b := w.Spawn([]ErasedComponent{Named("B"), ChildOf{Parent: root}})
+	c := w.Spawn([]ErasedComponent{Named("C"), ChildOf{Parent: a}})
+
+	w.RunSystem(func(h Hierarchy) {
+		require.Equal(t, []EntityId{a, root}, slices.Collect(h.Ancestors(c)))
+		require.Equal(t, []EntityId{a, c, b}, slices.Collect(h.Descendants(root)))
+		require.Equal(t, []EntityId{a, b, c}, slices.Collect(h.DescendantsBreadthFirst(root)))
+		require.Equal(t, []EntityId{b}, slices.Collect(h.Siblings(a)))
+		require.Equal(t, root, h.Root(c))
+	})
+
+	// move c from a to root
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(root).InsertChildAt(0, c)
+	})
+
+	require.Equal(t, []EntityId{c, a, b}, w.Hierarchy().Children(root))
+	require.Empty(t, w.Hierarchy().Children(a))
+
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(root).ReplaceChildren(b, a)
+		commands.Entity(a).SetParent(b)
+	})
+
+	require.Equal(t, []EntityId{b}, w.Hierarchy().Children(root))
+	require.Equal(t, []EntityId{a}, w.Hierarchy().Children(b))
+	require.Equal(t, c, w.Hierarchy().Root(c))
+
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(a).RemoveParent()
+	})
+
+	require.Empty(t, w.Hierarchy().Children(b))
+	require.NotPanics(t, func() { w.SetParent(a, root) })
+	require.Panics(t, func() { w.SetParent(root, a) })
+
+	// commands creating a cycle are skipped
+	require.NotPanics(t, func() {
+		w.RunSystem(func(commands *Commands) {
+			commands.Entity(root).SetParent(a)
+			commands.Entity(a).InsertChildAt(0, root)
+			commands.Entity(a).ReplaceChildren(root)
+		})
+	})
+
+	require.Equal(t, root, w.Hierarchy().Root(a))
+	require.Equal(t, []EntityId{b, a}, w.Hierarchy().Children(root))
+}
+
+func TestDespawnVariants(t *testing.T) {
+	w := NewWorld()
+
+	root := w.Spawn([]ErasedComponent{Named("Root")})
+	a := w.Spawn([]ErasedComponent{Named("A"), ChildOf{Parent: root}})
+	b := w.Spawn([]ErasedComponent{Named("B"), ChildOf{Parent: a}})
+	c := w.Spawn([]ErasedComponent{Named("C"), ChildOf{Parent: b}})
+
+	// b is moved to root
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(a).DespawnOnly()
+	})
+
+	require.Equal(t, []EntityId{b}, w.Hierarchy().Children(root))
+	require.ErrorIs(t, w.TryDespawn(a), ErrNoSuchEntity)
+
+	// c is despawned, b stays
+	w.RunSystem(func(commands *Commands) {
+		commands.Entity(b).DespawnDescendants()
+	})
+
+	require.Empty(t, w.Hierarchy().Children(b))
+	require.ErrorIs(t, w.TryDespawn(c), ErrNoSuchEntity)
+
+	// root is despawned with all its children
+	require.NoError(t, w.TryDespawn(root))
+	require.ErrorIs(t, w.TryDespawn(b), ErrNoSuchEntity)
+}
+
+type Backpack struct {
+	Component[Backpack]
+	Items []string
+}
+
+func TestMutChangeDetection(t *testing.T) {
+	w := NewWorld()
+
+	a := w.Spawn([]ErasedComponent{Backpack{}})
+	b := w.Spawn([]ErasedComponent{Backpack{}})
+
+	var changed []EntityId
+	detectChanges := func(q Query[struct {
+		EntityId EntityId
+		_        Changed[Backpack]
+	}]) {
+		changed = changed[:0]
+		for item := range q.Items() {
+			changed = append(changed, item.EntityId)
+		}
+	}
+
+	w.AddSystems(Update, detectChanges)
+
+	// first run sees both as changed, as they were added
+	w.RunSchedule(Update)
+	require.Equal(t, []EntityId{a, b}, changed)
+
+	w.RunSchedule(Update)
+	require.Empty(t, changed)
+
+	w.RunSystem(func(q Query[struct {
+		EntityId EntityId
+		Backpack Mut[Backpack]
+	}]) {
+		for item := range q.Items() {
+			if item.EntityId == a {
+				item.Backpack.Get().Items = append(item.Backpack.Get().Items, "Sword")
+			} else {
+				item.Backpack.BypassChangeDetection().Items = nil
+			}
+		}
+	})
+
+	w.RunSchedule(Update)
+	require.Equal(t, []EntityId{a}, changed)
+}
+
+func TestTicks(t *testing.T) {
+	w := NewWorld()
+
+	a := w.Spawn([]ErasedComponent{Position{X: 1}})
+
+	var changed, added []EntityId
+	detectChanges := func(q Query[struct {
+		EntityId EntityId
+		Ticks    Ticks[Position]
+	}]) {
+		changed, added = changed[:0], added[:0]
+
+		for item := range q.Items() {
+			require.NotEqual(t, spoke.NoTick, item.Ticks.Added())
+			require.GreaterOrEqual(t, item.Ticks.Changed(), item.Ticks.Added())
+
+			if item.Ticks.IsChanged() {
+				changed = append(changed, item.EntityId)
+			}
+
+			if item.Ticks.IsAdded() {
+				added = append(added, item.EntityId)
+			}
+		}
+	}
+
+	w.AddSystems(Update, detectChanges)
+
+	w.RunSchedule(Update)
+	require.Equal(t, []EntityId{a}, changed)
+	require.Equal(t, []EntityId{a}, added)
+
+	b := w.Spawn([]ErasedComponent{Position{X: 2}})
+
+	w.RunSystem(func(q Query[*Position]) {
+		for pos := range q.Items() {
+			if pos.X == 1 {
+				pos.X = 10
+			}
+		}
+	})
+
+	w.RunSchedule(Update)
+	require.ElementsMatch(t, []EntityId{a, b}, changed)
+	require.Equal(t, []EntityId{b}, added)
+}